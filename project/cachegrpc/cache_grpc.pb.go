@@ -25,7 +25,32 @@ type CacheServerClient interface {
 	GetClientID(ctx context.Context, in *AssignClientID, opts ...grpc.CallOption) (*AssignedClientID, error)
 	SetItem(ctx context.Context, in *SetItemParams, opts ...grpc.CallOption) (*SetItemResult, error)
 	GetItem(ctx context.Context, in *GetItemParams, opts ...grpc.CallOption) (*GetItemResult, error)
-	SubscribeItem(ctx context.Context, in *GetItemParams, opts ...grpc.CallOption) (CacheServer_SubscribeItemClient, error)
+	// Every event carries the sequence number of the change. With initial, the
+	// first event is the current value of the item (CURRENT), or that it has none
+	// (ABSENT). With resume, the first events are the changes after
+	// from_sequence, so that a stream that broke off can be continued without
+	// missing any; this fails with OutOfRange if they are no longer kept
+	SubscribeItem(ctx context.Context, in *SubscribeParams, opts ...grpc.CallOption) (CacheServer_SubscribeItemClient, error)
+	DeleteItem(ctx context.Context, in *GetItemParams, opts ...grpc.CallOption) (*DeleteItemResult, error)
+	// The owner, service and name may contain * and ? wildcards
+	SubscribePattern(ctx context.Context, in *GetItemParams, opts ...grpc.CallOption) (CacheServer_SubscribePatternClient, error)
+	// Many subscriptions over one stream. The client sends requests to add item
+	// and pattern watches, which work like SubscribeItem and SubscribePattern, or
+	// to remove them, at any time, and receives the events of all of them, each
+	// tagged with the watch_id the client chose for the watch
+	Watch(ctx context.Context, opts ...grpc.CallOption) (CacheServer_WatchClient, error)
+	MultiGet(ctx context.Context, in *MultiGetParams, opts ...grpc.CallOption) (*MultiGetResult, error)
+	MultiSet(ctx context.Context, in *MultiSetParams, opts ...grpc.CallOption) (*MultiSetResult, error)
+	Increment(ctx context.Context, in *IncrementParams, opts ...grpc.CallOption) (*IncrementResult, error)
+	// Shares the caller's items of one service with another client
+	Grant(ctx context.Context, in *GrantParams, opts ...grpc.CallOption) (*GrantResult, error)
+	// Changes the expiry of an item without changing its value
+	Touch(ctx context.Context, in *TouchParams, opts ...grpc.CallOption) (*TouchResult, error)
+	// Returns an item and changes its expiry, for sliding expiry. Unlike Touch,
+	// it needs an expiry or a ttl
+	GetAndTouch(ctx context.Context, in *TouchParams, opts ...grpc.CallOption) (*GetItemResult, error)
+	// Returns how long an item has left to live
+	TTL(ctx context.Context, in *GetItemParams, opts ...grpc.CallOption) (*TTLResult, error)
 }
 
 type cacheServerClient struct {
@@ -63,7 +88,7 @@ func (c *cacheServerClient) GetItem(ctx context.Context, in *GetItemParams, opts
 	return out, nil
 }
 
-func (c *cacheServerClient) SubscribeItem(ctx context.Context, in *GetItemParams, opts ...grpc.CallOption) (CacheServer_SubscribeItemClient, error) {
+func (c *cacheServerClient) SubscribeItem(ctx context.Context, in *SubscribeParams, opts ...grpc.CallOption) (CacheServer_SubscribeItemClient, error) {
 	stream, err := c.cc.NewStream(ctx, &CacheServer_ServiceDesc.Streams[0], "/cachegrpc.CacheServer/SubscribeItem", opts...)
 	if err != nil {
 		return nil, err
@@ -95,6 +120,141 @@ func (x *cacheServerSubscribeItemClient) Recv() (*GetItemResult, error) {
 	return m, nil
 }
 
+func (c *cacheServerClient) DeleteItem(ctx context.Context, in *GetItemParams, opts ...grpc.CallOption) (*DeleteItemResult, error) {
+	out := new(DeleteItemResult)
+	err := c.cc.Invoke(ctx, "/cachegrpc.CacheServer/DeleteItem", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *cacheServerClient) SubscribePattern(ctx context.Context, in *GetItemParams, opts ...grpc.CallOption) (CacheServer_SubscribePatternClient, error) {
+	stream, err := c.cc.NewStream(ctx, &CacheServer_ServiceDesc.Streams[1], "/cachegrpc.CacheServer/SubscribePattern", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &cacheServerSubscribePatternClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type CacheServer_SubscribePatternClient interface {
+	Recv() (*PatternEvent, error)
+	grpc.ClientStream
+}
+
+type cacheServerSubscribePatternClient struct {
+	grpc.ClientStream
+}
+
+func (x *cacheServerSubscribePatternClient) Recv() (*PatternEvent, error) {
+	m := new(PatternEvent)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *cacheServerClient) Watch(ctx context.Context, opts ...grpc.CallOption) (CacheServer_WatchClient, error) {
+	stream, err := c.cc.NewStream(ctx, &CacheServer_ServiceDesc.Streams[2], "/cachegrpc.CacheServer/Watch", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &cacheServerWatchClient{stream}
+	return x, nil
+}
+
+type CacheServer_WatchClient interface {
+	Send(*WatchRequest) error
+	Recv() (*WatchEvent, error)
+	grpc.ClientStream
+}
+
+type cacheServerWatchClient struct {
+	grpc.ClientStream
+}
+
+func (x *cacheServerWatchClient) Send(m *WatchRequest) error {
+	return x.ClientStream.SendMsg(m)
+}
+
+func (x *cacheServerWatchClient) Recv() (*WatchEvent, error) {
+	m := new(WatchEvent)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *cacheServerClient) MultiGet(ctx context.Context, in *MultiGetParams, opts ...grpc.CallOption) (*MultiGetResult, error) {
+	out := new(MultiGetResult)
+	err := c.cc.Invoke(ctx, "/cachegrpc.CacheServer/MultiGet", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *cacheServerClient) MultiSet(ctx context.Context, in *MultiSetParams, opts ...grpc.CallOption) (*MultiSetResult, error) {
+	out := new(MultiSetResult)
+	err := c.cc.Invoke(ctx, "/cachegrpc.CacheServer/MultiSet", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *cacheServerClient) Increment(ctx context.Context, in *IncrementParams, opts ...grpc.CallOption) (*IncrementResult, error) {
+	out := new(IncrementResult)
+	err := c.cc.Invoke(ctx, "/cachegrpc.CacheServer/Increment", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *cacheServerClient) Grant(ctx context.Context, in *GrantParams, opts ...grpc.CallOption) (*GrantResult, error) {
+	out := new(GrantResult)
+	err := c.cc.Invoke(ctx, "/cachegrpc.CacheServer/Grant", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *cacheServerClient) Touch(ctx context.Context, in *TouchParams, opts ...grpc.CallOption) (*TouchResult, error) {
+	out := new(TouchResult)
+	err := c.cc.Invoke(ctx, "/cachegrpc.CacheServer/Touch", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *cacheServerClient) GetAndTouch(ctx context.Context, in *TouchParams, opts ...grpc.CallOption) (*GetItemResult, error) {
+	out := new(GetItemResult)
+	err := c.cc.Invoke(ctx, "/cachegrpc.CacheServer/GetAndTouch", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *cacheServerClient) TTL(ctx context.Context, in *GetItemParams, opts ...grpc.CallOption) (*TTLResult, error) {
+	out := new(TTLResult)
+	err := c.cc.Invoke(ctx, "/cachegrpc.CacheServer/TTL", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // CacheServerServer is the server API for CacheServer service.
 // All implementations must embed UnimplementedCacheServerServer
 // for forward compatibility
@@ -102,7 +262,32 @@ type CacheServerServer interface {
 	GetClientID(context.Context, *AssignClientID) (*AssignedClientID, error)
 	SetItem(context.Context, *SetItemParams) (*SetItemResult, error)
 	GetItem(context.Context, *GetItemParams) (*GetItemResult, error)
-	SubscribeItem(*GetItemParams, CacheServer_SubscribeItemServer) error
+	// Every event carries the sequence number of the change. With initial, the
+	// first event is the current value of the item (CURRENT), or that it has none
+	// (ABSENT). With resume, the first events are the changes after
+	// from_sequence, so that a stream that broke off can be continued without
+	// missing any; this fails with OutOfRange if they are no longer kept
+	SubscribeItem(*SubscribeParams, CacheServer_SubscribeItemServer) error
+	DeleteItem(context.Context, *GetItemParams) (*DeleteItemResult, error)
+	// The owner, service and name may contain * and ? wildcards
+	SubscribePattern(*GetItemParams, CacheServer_SubscribePatternServer) error
+	// Many subscriptions over one stream. The client sends requests to add item
+	// and pattern watches, which work like SubscribeItem and SubscribePattern, or
+	// to remove them, at any time, and receives the events of all of them, each
+	// tagged with the watch_id the client chose for the watch
+	Watch(CacheServer_WatchServer) error
+	MultiGet(context.Context, *MultiGetParams) (*MultiGetResult, error)
+	MultiSet(context.Context, *MultiSetParams) (*MultiSetResult, error)
+	Increment(context.Context, *IncrementParams) (*IncrementResult, error)
+	// Shares the caller's items of one service with another client
+	Grant(context.Context, *GrantParams) (*GrantResult, error)
+	// Changes the expiry of an item without changing its value
+	Touch(context.Context, *TouchParams) (*TouchResult, error)
+	// Returns an item and changes its expiry, for sliding expiry. Unlike Touch,
+	// it needs an expiry or a ttl
+	GetAndTouch(context.Context, *TouchParams) (*GetItemResult, error)
+	// Returns how long an item has left to live
+	TTL(context.Context, *GetItemParams) (*TTLResult, error)
 	mustEmbedUnimplementedCacheServerServer()
 }
 
@@ -119,9 +304,39 @@ func (UnimplementedCacheServerServer) SetItem(context.Context, *SetItemParams) (
 func (UnimplementedCacheServerServer) GetItem(context.Context, *GetItemParams) (*GetItemResult, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method GetItem not implemented")
 }
-func (UnimplementedCacheServerServer) SubscribeItem(*GetItemParams, CacheServer_SubscribeItemServer) error {
+func (UnimplementedCacheServerServer) SubscribeItem(*SubscribeParams, CacheServer_SubscribeItemServer) error {
 	return status.Errorf(codes.Unimplemented, "method SubscribeItem not implemented")
 }
+func (UnimplementedCacheServerServer) DeleteItem(context.Context, *GetItemParams) (*DeleteItemResult, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteItem not implemented")
+}
+func (UnimplementedCacheServerServer) SubscribePattern(*GetItemParams, CacheServer_SubscribePatternServer) error {
+	return status.Errorf(codes.Unimplemented, "method SubscribePattern not implemented")
+}
+func (UnimplementedCacheServerServer) Watch(CacheServer_WatchServer) error {
+	return status.Errorf(codes.Unimplemented, "method Watch not implemented")
+}
+func (UnimplementedCacheServerServer) MultiGet(context.Context, *MultiGetParams) (*MultiGetResult, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method MultiGet not implemented")
+}
+func (UnimplementedCacheServerServer) MultiSet(context.Context, *MultiSetParams) (*MultiSetResult, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method MultiSet not implemented")
+}
+func (UnimplementedCacheServerServer) Increment(context.Context, *IncrementParams) (*IncrementResult, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Increment not implemented")
+}
+func (UnimplementedCacheServerServer) Grant(context.Context, *GrantParams) (*GrantResult, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Grant not implemented")
+}
+func (UnimplementedCacheServerServer) Touch(context.Context, *TouchParams) (*TouchResult, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Touch not implemented")
+}
+func (UnimplementedCacheServerServer) GetAndTouch(context.Context, *TouchParams) (*GetItemResult, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetAndTouch not implemented")
+}
+func (UnimplementedCacheServerServer) TTL(context.Context, *GetItemParams) (*TTLResult, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method TTL not implemented")
+}
 func (UnimplementedCacheServerServer) mustEmbedUnimplementedCacheServerServer() {}
 
 // UnsafeCacheServerServer may be embedded to opt out of forward compatibility for this service.
@@ -190,7 +405,7 @@ func _CacheServer_GetItem_Handler(srv interface{}, ctx context.Context, dec func
 }
 
 func _CacheServer_SubscribeItem_Handler(srv interface{}, stream grpc.ServerStream) error {
-	m := new(GetItemParams)
+	m := new(SubscribeParams)
 	if err := stream.RecvMsg(m); err != nil {
 		return err
 	}
@@ -210,6 +425,197 @@ func (x *cacheServerSubscribeItemServer) Send(m *GetItemResult) error {
 	return x.ServerStream.SendMsg(m)
 }
 
+func _CacheServer_DeleteItem_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetItemParams)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CacheServerServer).DeleteItem(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/cachegrpc.CacheServer/DeleteItem",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CacheServerServer).DeleteItem(ctx, req.(*GetItemParams))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CacheServer_SubscribePattern_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(GetItemParams)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(CacheServerServer).SubscribePattern(m, &cacheServerSubscribePatternServer{stream})
+}
+
+type CacheServer_SubscribePatternServer interface {
+	Send(*PatternEvent) error
+	grpc.ServerStream
+}
+
+type cacheServerSubscribePatternServer struct {
+	grpc.ServerStream
+}
+
+func (x *cacheServerSubscribePatternServer) Send(m *PatternEvent) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _CacheServer_Watch_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(CacheServerServer).Watch(&cacheServerWatchServer{stream})
+}
+
+type CacheServer_WatchServer interface {
+	Send(*WatchEvent) error
+	Recv() (*WatchRequest, error)
+	grpc.ServerStream
+}
+
+type cacheServerWatchServer struct {
+	grpc.ServerStream
+}
+
+func (x *cacheServerWatchServer) Send(m *WatchEvent) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func (x *cacheServerWatchServer) Recv() (*WatchRequest, error) {
+	m := new(WatchRequest)
+	if err := x.ServerStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func _CacheServer_MultiGet_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(MultiGetParams)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CacheServerServer).MultiGet(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/cachegrpc.CacheServer/MultiGet",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CacheServerServer).MultiGet(ctx, req.(*MultiGetParams))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CacheServer_MultiSet_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(MultiSetParams)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CacheServerServer).MultiSet(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/cachegrpc.CacheServer/MultiSet",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CacheServerServer).MultiSet(ctx, req.(*MultiSetParams))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CacheServer_Increment_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(IncrementParams)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CacheServerServer).Increment(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/cachegrpc.CacheServer/Increment",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CacheServerServer).Increment(ctx, req.(*IncrementParams))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CacheServer_Grant_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GrantParams)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CacheServerServer).Grant(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/cachegrpc.CacheServer/Grant",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CacheServerServer).Grant(ctx, req.(*GrantParams))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CacheServer_Touch_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(TouchParams)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CacheServerServer).Touch(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/cachegrpc.CacheServer/Touch",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CacheServerServer).Touch(ctx, req.(*TouchParams))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CacheServer_GetAndTouch_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(TouchParams)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CacheServerServer).GetAndTouch(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/cachegrpc.CacheServer/GetAndTouch",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CacheServerServer).GetAndTouch(ctx, req.(*TouchParams))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CacheServer_TTL_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetItemParams)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CacheServerServer).TTL(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/cachegrpc.CacheServer/TTL",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CacheServerServer).TTL(ctx, req.(*GetItemParams))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // CacheServer_ServiceDesc is the grpc.ServiceDesc for CacheServer service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -229,6 +635,38 @@ var CacheServer_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "GetItem",
 			Handler:    _CacheServer_GetItem_Handler,
 		},
+		{
+			MethodName: "DeleteItem",
+			Handler:    _CacheServer_DeleteItem_Handler,
+		},
+		{
+			MethodName: "MultiGet",
+			Handler:    _CacheServer_MultiGet_Handler,
+		},
+		{
+			MethodName: "MultiSet",
+			Handler:    _CacheServer_MultiSet_Handler,
+		},
+		{
+			MethodName: "Increment",
+			Handler:    _CacheServer_Increment_Handler,
+		},
+		{
+			MethodName: "Grant",
+			Handler:    _CacheServer_Grant_Handler,
+		},
+		{
+			MethodName: "Touch",
+			Handler:    _CacheServer_Touch_Handler,
+		},
+		{
+			MethodName: "GetAndTouch",
+			Handler:    _CacheServer_GetAndTouch_Handler,
+		},
+		{
+			MethodName: "TTL",
+			Handler:    _CacheServer_TTL_Handler,
+		},
 	},
 	Streams: []grpc.StreamDesc{
 		{
@@ -236,6 +674,251 @@ var CacheServer_ServiceDesc = grpc.ServiceDesc{
 			Handler:       _CacheServer_SubscribeItem_Handler,
 			ServerStreams: true,
 		},
+		{
+			StreamName:    "SubscribePattern",
+			Handler:       _CacheServer_SubscribePattern_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "Watch",
+			Handler:       _CacheServer_Watch_Handler,
+			ServerStreams: true,
+			ClientStreams: true,
+		},
+	},
+	Metadata: "cache.proto",
+}
+
+// AdminClient is the client API for Admin service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type AdminClient interface {
+	Stats(ctx context.Context, in *StatsParams, opts ...grpc.CallOption) (*StatsResult, error)
+	// Remove every item, every item of params.owner, or every item of
+	// params.owner under params.service
+	FlushAll(ctx context.Context, in *FlushParams, opts ...grpc.CallOption) (*FlushResult, error)
+	FlushOwner(ctx context.Context, in *FlushParams, opts ...grpc.CallOption) (*FlushResult, error)
+	FlushService(ctx context.Context, in *FlushParams, opts ...grpc.CallOption) (*FlushResult, error)
+	ListKeys(ctx context.Context, in *ListKeysParams, opts ...grpc.CallOption) (*ListKeysResult, error)
+}
+
+type adminClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewAdminClient(cc grpc.ClientConnInterface) AdminClient {
+	return &adminClient{cc}
+}
+
+func (c *adminClient) Stats(ctx context.Context, in *StatsParams, opts ...grpc.CallOption) (*StatsResult, error) {
+	out := new(StatsResult)
+	err := c.cc.Invoke(ctx, "/cachegrpc.Admin/Stats", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminClient) FlushAll(ctx context.Context, in *FlushParams, opts ...grpc.CallOption) (*FlushResult, error) {
+	out := new(FlushResult)
+	err := c.cc.Invoke(ctx, "/cachegrpc.Admin/FlushAll", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminClient) FlushOwner(ctx context.Context, in *FlushParams, opts ...grpc.CallOption) (*FlushResult, error) {
+	out := new(FlushResult)
+	err := c.cc.Invoke(ctx, "/cachegrpc.Admin/FlushOwner", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminClient) FlushService(ctx context.Context, in *FlushParams, opts ...grpc.CallOption) (*FlushResult, error) {
+	out := new(FlushResult)
+	err := c.cc.Invoke(ctx, "/cachegrpc.Admin/FlushService", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminClient) ListKeys(ctx context.Context, in *ListKeysParams, opts ...grpc.CallOption) (*ListKeysResult, error) {
+	out := new(ListKeysResult)
+	err := c.cc.Invoke(ctx, "/cachegrpc.Admin/ListKeys", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// AdminServer is the server API for Admin service.
+// All implementations must embed UnimplementedAdminServer
+// for forward compatibility
+type AdminServer interface {
+	Stats(context.Context, *StatsParams) (*StatsResult, error)
+	// Remove every item, every item of params.owner, or every item of
+	// params.owner under params.service
+	FlushAll(context.Context, *FlushParams) (*FlushResult, error)
+	FlushOwner(context.Context, *FlushParams) (*FlushResult, error)
+	FlushService(context.Context, *FlushParams) (*FlushResult, error)
+	ListKeys(context.Context, *ListKeysParams) (*ListKeysResult, error)
+	mustEmbedUnimplementedAdminServer()
+}
+
+// UnimplementedAdminServer must be embedded to have forward compatible implementations.
+type UnimplementedAdminServer struct {
+}
+
+func (UnimplementedAdminServer) Stats(context.Context, *StatsParams) (*StatsResult, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Stats not implemented")
+}
+func (UnimplementedAdminServer) FlushAll(context.Context, *FlushParams) (*FlushResult, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method FlushAll not implemented")
+}
+func (UnimplementedAdminServer) FlushOwner(context.Context, *FlushParams) (*FlushResult, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method FlushOwner not implemented")
+}
+func (UnimplementedAdminServer) FlushService(context.Context, *FlushParams) (*FlushResult, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method FlushService not implemented")
+}
+func (UnimplementedAdminServer) ListKeys(context.Context, *ListKeysParams) (*ListKeysResult, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListKeys not implemented")
+}
+func (UnimplementedAdminServer) mustEmbedUnimplementedAdminServer() {}
+
+// UnsafeAdminServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to AdminServer will
+// result in compilation errors.
+type UnsafeAdminServer interface {
+	mustEmbedUnimplementedAdminServer()
+}
+
+func RegisterAdminServer(s grpc.ServiceRegistrar, srv AdminServer) {
+	s.RegisterService(&Admin_ServiceDesc, srv)
+}
+
+func _Admin_Stats_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(StatsParams)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServer).Stats(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/cachegrpc.Admin/Stats",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServer).Stats(ctx, req.(*StatsParams))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Admin_FlushAll_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(FlushParams)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServer).FlushAll(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/cachegrpc.Admin/FlushAll",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServer).FlushAll(ctx, req.(*FlushParams))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Admin_FlushOwner_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(FlushParams)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServer).FlushOwner(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/cachegrpc.Admin/FlushOwner",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServer).FlushOwner(ctx, req.(*FlushParams))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Admin_FlushService_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(FlushParams)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServer).FlushService(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/cachegrpc.Admin/FlushService",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServer).FlushService(ctx, req.(*FlushParams))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Admin_ListKeys_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListKeysParams)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServer).ListKeys(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/cachegrpc.Admin/ListKeys",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServer).ListKeys(ctx, req.(*ListKeysParams))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// Admin_ServiceDesc is the grpc.ServiceDesc for Admin service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var Admin_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "cachegrpc.Admin",
+	HandlerType: (*AdminServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Stats",
+			Handler:    _Admin_Stats_Handler,
+		},
+		{
+			MethodName: "FlushAll",
+			Handler:    _Admin_FlushAll_Handler,
+		},
+		{
+			MethodName: "FlushOwner",
+			Handler:    _Admin_FlushOwner_Handler,
+		},
+		{
+			MethodName: "FlushService",
+			Handler:    _Admin_FlushService_Handler,
+		},
+		{
+			MethodName: "ListKeys",
+			Handler:    _Admin_ListKeys_Handler,
+		},
 	},
+	Streams:  []grpc.StreamDesc{},
 	Metadata: "cache.proto",
 }
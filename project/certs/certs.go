@@ -0,0 +1,136 @@
+// Package certs loads the TLS certificates of the cache server and client from
+// PEM files, and can reload them while running, so that renewed certificates
+// are picked up without a restart
+package certs
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"log"
+	"os"
+	"os/signal"
+	"sync"
+)
+
+// A Reloader holds a certificate with its key, and optionally a pool of CA
+// certificates used to verify the other side of a connection. The TLS
+// configurations it returns always use the most recently loaded files
+type Reloader struct {
+	certFile string
+	keyFile  string
+	caFile   string
+
+	lock sync.RWMutex
+	cert *tls.Certificate
+	pool *x509.CertPool
+}
+
+// NewReloader loads the certificate and key from certFile and keyFile, and the CA
+// certificates from caFile. Any of the files may be empty: a server always needs
+// a certificate, while a client only needs one for mutual TLS
+func NewReloader(certFile, keyFile, caFile string) (*Reloader, error) {
+	if (certFile == "") != (keyFile == "") {
+		return nil, errors.New("a certificate and its key must be given together")
+	}
+	r := &Reloader{certFile: certFile, keyFile: keyFile, caFile: caFile}
+	if err := r.Reload(); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// Reload reads all the files again. If any of them fails to load, the
+// previously loaded certificates stay in use
+func (r *Reloader) Reload() error {
+	var cert *tls.Certificate
+	if r.certFile != "" {
+		c, err := tls.LoadX509KeyPair(r.certFile, r.keyFile)
+		if err != nil {
+			return err
+		}
+		cert = &c
+	}
+	var pool *x509.CertPool
+	if r.caFile != "" {
+		pem, err := os.ReadFile(r.caFile)
+		if err != nil {
+			return err
+		}
+		pool = x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return errors.New("no certificates found in " + r.caFile)
+		}
+	}
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	r.cert = cert
+	r.pool = pool
+	return nil
+}
+
+// ReloadOnSignal reloads the files every time one of the given signals, usually
+// SIGHUP, is received
+func (r *Reloader) ReloadOnSignal(sig ...os.Signal) {
+	ch := make(chan os.Signal, 1)
+	signal.Notify(ch, sig...)
+	go func() {
+		for range ch {
+			if err := r.Reload(); err != nil {
+				log.Printf("Failed to reload certificates, keeping the old ones: %v\n", err)
+				continue
+			}
+			log.Printf("Reloaded certificates\n")
+		}
+	}()
+}
+
+func (r *Reloader) current() (*tls.Certificate, *x509.CertPool) {
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+	return r.cert, r.pool
+}
+
+// ServerConfig returns a TLS configuration for the server. If there is a CA file,
+// clients may present a certificate, which is then verified against it; clients
+// without a certificate are still accepted, and must be rejected by the server
+// unless it identifies them otherwise, see server.CacheServer.RequireClientCerts
+func (r *Reloader) ServerConfig() *tls.Config {
+	return &tls.Config{
+		MinVersion: tls.VersionTLS12,
+		GetConfigForClient: func(*tls.ClientHelloInfo) (*tls.Config, error) {
+			cert, pool := r.current()
+			if cert == nil {
+				return nil, errors.New("no server certificate loaded")
+			}
+			cfg := &tls.Config{MinVersion: tls.VersionTLS12, Certificates: []tls.Certificate{*cert}}
+			if pool != nil {
+				cfg.ClientCAs = pool
+				cfg.ClientAuth = tls.VerifyClientCertIfGiven
+			}
+			return cfg, nil
+		},
+	}
+}
+
+// ClientConfig returns a TLS configuration for a client connecting to
+// serverName. The server certificate is verified against the CA file if there
+// is one, or the system CAs otherwise. The client certificate, if any, is sent
+// for mutual TLS. A reload only affects the client certificate, as the CAs are
+// fixed once the configuration is created
+func (r *Reloader) ClientConfig(serverName string) *tls.Config {
+	_, pool := r.current()
+	return &tls.Config{
+		MinVersion: tls.VersionTLS12,
+		ServerName: serverName,
+		RootCAs:    pool,
+		GetClientCertificate: func(*tls.CertificateRequestInfo) (*tls.Certificate, error) {
+			cert, _ := r.current()
+			if cert == nil {
+				// Continue without a certificate, the server may not need one
+				return &tls.Certificate{}, nil
+			}
+			return cert, nil
+		},
+	}
+}
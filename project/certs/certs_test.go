@@ -0,0 +1,232 @@
+package certs_test
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/kamenlilovgocourse/gocourse/project/cachegrpc"
+	"github.com/kamenlilovgocourse/gocourse/project/certs"
+	"github.com/kamenlilovgocourse/gocourse/project/server"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// A locally generated certificate with its key
+type testCert struct {
+	cert *x509.Certificate
+	key  *ecdsa.PrivateKey
+}
+
+// Generate a certificate with the given common name, signed by parent, or self
+// signed if parent is nil. A self signed certificate is made a CA
+func newCert(t *testing.T, cn string, parent *testCert) *testCert {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	serial, _ := rand.Int(rand.Reader, big.NewInt(1<<62))
+	tmpl := &x509.Certificate{
+		SerialNumber: serial,
+		Subject:      pkix.Name{CommonName: cn},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+		DNSNames:     []string{"localhost"},
+		IPAddresses:  []net.IP{net.ParseIP("127.0.0.1")},
+	}
+	signer, signerKey := tmpl, key
+	if parent == nil {
+		tmpl.IsCA = true
+		tmpl.BasicConstraintsValid = true
+	} else {
+		signer, signerKey = parent.cert, parent.key
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, signer, &key.PublicKey, signerKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &testCert{cert: cert, key: key}
+}
+
+// Write the certificate and key as PEM files into dir, and return their paths
+func (c *testCert) write(t *testing.T, dir, name string) (certFile, keyFile string) {
+	certFile = filepath.Join(dir, name+".crt")
+	keyFile = filepath.Join(dir, name+".key")
+	keyDer, err := x509.MarshalECPrivateKey(c.key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: c.cert.Raw}), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDer}), 0600); err != nil {
+		t.Fatal(err)
+	}
+	return certFile, keyFile
+}
+
+// Test that Reload picks up new certificates, and keeps the old ones when the
+// new files cannot be loaded
+func TestReload(t *testing.T) {
+	dir := t.TempDir()
+	ca := newCert(t, "ca", nil)
+	caFile, _ := ca.write(t, dir, "ca")
+	certFile, keyFile := newCert(t, "first", ca).write(t, dir, "server")
+	r, err := certs.NewReloader(certFile, keyFile, caFile)
+	if err != nil {
+		t.Fatalf("NewReloader() returned error %v", err)
+	}
+	serverName := func() string {
+		cfg, err := r.ServerConfig().GetConfigForClient(&tls.ClientHelloInfo{})
+		if err != nil {
+			t.Fatalf("GetConfigForClient() returned error %v", err)
+		}
+		cert, _ := x509.ParseCertificate(cfg.Certificates[0].Certificate[0])
+		return cert.Subject.CommonName
+	}
+	if serverName() != "first" {
+		t.Fatalf("ServerConfig() did not use the loaded certificate")
+	}
+
+	newCert(t, "second", ca).write(t, dir, "server")
+	if err := r.Reload(); err != nil {
+		t.Fatalf("Reload() returned error %v", err)
+	}
+	if serverName() != "second" {
+		t.Fatalf("Reload() did not pick up the new certificate")
+	}
+
+	os.WriteFile(keyFile, []byte("garbage"), 0600)
+	if err := r.Reload(); err == nil {
+		t.Fatalf("Reload() of a broken key did not fail")
+	}
+	if serverName() != "second" {
+		t.Fatalf("failed Reload() dropped the previous certificate")
+	}
+}
+
+// Start a gRPC cache server that verifies client certificates against caFile,
+// and return its address. setup configures the CacheServer before it serves
+func serveMutualTLS(t *testing.T, serverCert, serverKey, caFile string, setup func(*server.CacheServer)) string {
+	sr, err := certs.NewReloader(serverCert, serverKey, caFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	cache := server.New()
+	t.Cleanup(func() { cache.Close() })
+	cacheServer := server.NewServer(cache)
+	if setup != nil {
+		setup(cacheServer)
+	}
+	grpcServer := grpc.NewServer(
+		grpc.Creds(credentials.NewTLS(sr.ServerConfig())),
+		grpc.UnaryInterceptor(cacheServer.UnaryAuthInterceptor),
+		grpc.StreamInterceptor(cacheServer.StreamAuthInterceptor),
+	)
+	cachegrpc.RegisterCacheServerServer(grpcServer, cacheServer)
+	go grpcServer.Serve(lis)
+	t.Cleanup(grpcServer.Stop)
+	return lis.Addr().String()
+}
+
+// Connect to addr over TLS, with the client certificate, if any, and the CAs
+// in the given files
+func dialTLS(t *testing.T, addr, certFile, keyFile, caFile string) cachegrpc.CacheServerClient {
+	cr, err := certs.NewReloader(certFile, keyFile, caFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	conn, err := grpc.Dial(addr, grpc.WithTransportCredentials(credentials.NewTLS(cr.ClientConfig("localhost"))))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return cachegrpc.NewCacheServerClient(conn)
+}
+
+// Test that a client connecting with a certificate is identified by its common
+// name, and may only write its own items
+func TestClientCertificateOwner(t *testing.T) {
+	dir := t.TempDir()
+	ca := newCert(t, "ca", nil)
+	caFile, _ := ca.write(t, dir, "ca")
+	serverCert, serverKey := newCert(t, "localhost", ca).write(t, dir, "server")
+	clientCert, clientKey := newCert(t, "alice", ca).write(t, dir, "client")
+	addr := serveMutualTLS(t, serverCert, serverKey, caFile, nil)
+	client := dialTLS(t, addr, clientCert, clientKey, caFile)
+	ctx := context.Background()
+
+	id, err := client.GetClientID(ctx, &cachegrpc.AssignClientID{})
+	if err != nil {
+		t.Fatalf("GetClientID() over mutual TLS returned error %v", err)
+	}
+	if id.Id != "alice" {
+		t.Fatalf("GetClientID() returned %q, expected the certificate name alice", id.Id)
+	}
+	if _, err := client.SetItem(ctx, &cachegrpc.SetItemParams{Owner: "alice", Service: "s", Name: "n", Value: "v"}); err != nil {
+		t.Fatalf("SetItem() of own item returned error %v", err)
+	}
+	if _, err := client.SetItem(ctx, &cachegrpc.SetItemParams{Owner: "bob", Service: "s", Name: "n", Value: "v"}); status.Code(err) != codes.PermissionDenied {
+		t.Fatalf("SetItem() of another owner's item returned %v, expected PermissionDenied", err)
+	}
+}
+
+// Test that a client without a certificate is turned away by a server that
+// requires them, unless it authenticates with a token
+func TestClientWithoutCertificate(t *testing.T) {
+	dir := t.TempDir()
+	ca := newCert(t, "ca", nil)
+	caFile, _ := ca.write(t, dir, "ca")
+	serverCert, serverKey := newCert(t, "localhost", ca).write(t, dir, "server")
+	ctx := context.Background()
+
+	addr := serveMutualTLS(t, serverCert, serverKey, caFile, (*server.CacheServer).RequireClientCerts)
+	client := dialTLS(t, addr, "", "", caFile)
+	if _, err := client.SetItem(ctx, &cachegrpc.SetItemParams{Owner: "alice", Service: "s", Name: "n", Value: "v"}); status.Code(err) != codes.Unauthenticated {
+		t.Fatalf("SetItem() without a certificate returned %v, expected Unauthenticated", err)
+	}
+	if _, err := client.GetItem(ctx, &cachegrpc.GetItemParams{Owner: "alice", Service: "s", Name: "n"}); status.Code(err) != codes.Unauthenticated {
+		t.Fatalf("GetItem() without a certificate returned %v, expected Unauthenticated", err)
+	}
+
+	addr = serveMutualTLS(t, serverCert, serverKey, caFile, func(s *server.CacheServer) {
+		s.EnableAuth([]byte("secret"))
+		s.RequireClientCerts()
+	})
+	client = dialTLS(t, addr, "", "", caFile)
+	id, err := client.GetClientID(ctx, &cachegrpc.AssignClientID{})
+	if err != nil {
+		t.Fatalf("GetClientID() without a certificate, but with authentication, returned error %v", err)
+	}
+	tokenCtx := metadata.AppendToOutgoingContext(ctx, server.TokenMetadataKey, id.Token)
+	if _, err := client.SetItem(tokenCtx, &cachegrpc.SetItemParams{Owner: id.Id, Service: "s", Name: "n", Value: "v"}); err != nil {
+		t.Fatalf("SetItem() with a token instead of a certificate returned error %v", err)
+	}
+	if _, err := client.SetItem(tokenCtx, &cachegrpc.SetItemParams{Owner: "alice", Service: "s", Name: "n", Value: "v"}); status.Code(err) != codes.PermissionDenied {
+		t.Fatalf("SetItem() of another owner's item returned %v, expected PermissionDenied", err)
+	}
+}
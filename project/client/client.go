@@ -0,0 +1,342 @@
+// Package client is a Go client for the cache server. It offers the cache as
+// typed calls over item.ID rather than the generated cachegrpc messages, puts
+// a deadline on every call, retries the calls that are safe to repeat when the
+// server can not be reached, and keeps subscriptions going when the connection
+// to the server is lost and made again
+package client
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"github.com/kamenlilovgocourse/gocourse/project/cachegrpc"
+	"github.com/kamenlilovgocourse/gocourse/project/item"
+	"github.com/kamenlilovgocourse/gocourse/project/server"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/durationpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// Defaults of the Options that are left zero
+const (
+	DefaultTimeout    = 5 * time.Second
+	DefaultRetries    = 3
+	DefaultBackoff    = 100 * time.Millisecond
+	DefaultMaxBackoff = 5 * time.Second
+)
+
+// Largest response accepted from the server, which must hold the largest value
+// the server may be configured to store
+const maxMessageSize = 256 << 20
+
+// Reasons for which a call fails that callers are expected to handle. Other
+// failures are returned as gRPC status errors
+var (
+	ErrNotFound        = errors.New("item not found")
+	ErrConditionFailed = errors.New("condition of the set does not hold")
+)
+
+// Options control how a Client connects to the server and retries its calls
+type Options struct {
+	// Credentials of the connection, which is made without TLS if nil
+	Credentials credentials.TransportCredentials
+	// Token sent with every call to a server that requires authentication, as
+	// returned with the client ID by an earlier session. ClientID sets it
+	Token string
+	// Deadline of every call, unless its context has an earlier one
+	Timeout time.Duration
+	// How often a call that is safe to repeat is retried when the server can not
+	// be reached, or does not answer in time. Negative to never retry
+	Retries int
+	// Delay before the first retry, doubled for every further one up to
+	// MaxBackoff, and randomized so that many clients do not retry together
+	Backoff    time.Duration
+	MaxBackoff time.Duration
+	// Further options for grpc.Dial
+	DialOptions []grpc.DialOption
+}
+
+// A Client of the cache server. It is safe for concurrent use
+type Client struct {
+	conn  *grpc.ClientConn
+	cache cachegrpc.CacheServerClient
+	opts  Options
+
+	lock  sync.Mutex
+	token string
+}
+
+// Dial returns a Client of the server at addr, in the format host:port. The
+// connection is made in the background, and made again whenever it is lost,
+// for as long as the Client is not closed
+func Dial(addr string, opts Options) (*Client, error) {
+	if opts.Credentials == nil {
+		opts.Credentials = insecure.NewCredentials()
+	}
+	if opts.Timeout == 0 {
+		opts.Timeout = DefaultTimeout
+	}
+	if opts.Retries == 0 {
+		opts.Retries = DefaultRetries
+	}
+	if opts.Backoff == 0 {
+		opts.Backoff = DefaultBackoff
+	}
+	if opts.MaxBackoff == 0 {
+		opts.MaxBackoff = DefaultMaxBackoff
+	}
+	c := &Client{opts: opts, token: opts.Token}
+	dialOpts := []grpc.DialOption{
+		grpc.WithTransportCredentials(opts.Credentials),
+		grpc.WithUnaryInterceptor(c.unaryTokenInterceptor),
+		grpc.WithStreamInterceptor(c.streamTokenInterceptor),
+		grpc.WithDefaultCallOptions(grpc.MaxCallRecvMsgSize(maxMessageSize)),
+	}
+	conn, err := grpc.Dial(addr, append(dialOpts, opts.DialOptions...)...)
+	if err != nil {
+		return nil, err
+	}
+	c.conn = conn
+	c.cache = cachegrpc.NewCacheServerClient(conn)
+	return c, nil
+}
+
+// Close the connection to the server, which ends all subscriptions
+func (c *Client) Close() error {
+	return c.conn.Close()
+}
+
+// Conn returns the connection to the server, for calls that the Client does
+// not offer. The token is sent with them as with the calls of the Client, but
+// they are neither given a deadline nor retried
+func (c *Client) Conn() *grpc.ClientConn {
+	return c.conn
+}
+
+// Attach the token, if any, to the metadata of an outgoing call
+func (c *Client) withToken(ctx context.Context) context.Context {
+	c.lock.Lock()
+	token := c.token
+	c.lock.Unlock()
+	if token == "" {
+		return ctx
+	}
+	return metadata.AppendToOutgoingContext(ctx, server.TokenMetadataKey, token)
+}
+
+// Client interceptors sending the token with every unary and streaming call
+func (c *Client) unaryTokenInterceptor(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+	return invoker(c.withToken(ctx), method, req, reply, cc, opts...)
+}
+
+func (c *Client) streamTokenInterceptor(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+	return streamer(c.withToken(ctx), desc, cc, method, opts...)
+}
+
+// Return whether a failed call may succeed if made again: the server could not
+// be reached, or did not answer before the deadline of the attempt
+func retryable(err error) bool {
+	code := status.Code(err)
+	return code == codes.Unavailable || code == codes.DeadlineExceeded
+}
+
+// Return the delay before the given retry, counted from zero
+func (c *Client) backoff(retry int) time.Duration {
+	d := c.opts.Backoff
+	for i := 0; i < retry && d < c.opts.MaxBackoff; i++ {
+		d *= 2
+	}
+	if d > c.opts.MaxBackoff {
+		d = c.opts.MaxBackoff
+	}
+	// Anywhere from half the delay to all of it
+	return d/2 + time.Duration(rand.Int63n(int64(d/2)+1))
+}
+
+// Wait before the given retry of a call that failed with err, and return
+// whether to make it: if the error is worth retrying, retries are left and ctx
+// has not ended while waiting
+func (c *Client) waitRetry(ctx context.Context, retry int, err error) bool {
+	if !retryable(err) || retry >= c.opts.Retries || ctx.Err() != nil {
+		return false
+	}
+	t := time.NewTimer(c.backoff(retry))
+	defer t.Stop()
+	select {
+	case <-t.C:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
+
+// Make a call with the deadline of the Client
+func (c *Client) once(ctx context.Context, call func(ctx context.Context) error) error {
+	ctx, cancel := context.WithTimeout(ctx, c.opts.Timeout)
+	defer cancel()
+	return call(ctx)
+}
+
+// Make a call that is safe to repeat, retrying it while it fails in a way
+// that may go away
+func (c *Client) retry(ctx context.Context, call func(ctx context.Context) error) error {
+	for retry := 0; ; retry++ {
+		err := c.once(ctx, call)
+		if err == nil || !c.waitRetry(ctx, retry, err) {
+			return err
+		}
+	}
+}
+
+// ClientID asks the server for a client ID to use as the owner of items. A
+// server that requires authentication returns a token with it, which the
+// Client sends with every further call, and which Token returns so that it can
+// be passed in Options.Token to a later session to keep the ID
+func (c *Client) ClientID(ctx context.Context) (string, error) {
+	var res *cachegrpc.AssignedClientID
+	err := c.once(ctx, func(ctx context.Context) (err error) {
+		res, err = c.cache.GetClientID(ctx, &cachegrpc.AssignClientID{})
+		return err
+	})
+	if err != nil {
+		return "", err
+	}
+	if res.Token != "" {
+		c.lock.Lock()
+		c.token = res.Token
+		c.lock.Unlock()
+	}
+	return res.Id, nil
+}
+
+// Token returns the token sent with every call, if any
+func (c *Client) Token() string {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	return c.token
+}
+
+// An Item as stored in the cache. Expiry is nil for items that do not expire
+type Item struct {
+	Value       string
+	Expiry      *time.Time
+	Version     uint64
+	ContentType string
+	Flags       uint32
+}
+
+// SetOptions control how Set writes an item. The expiry is either an absolute
+// time in Expiry, or a time to live in TTL, which the server counts from when
+// it receives the call. IfAbsent only sets an item that has no value, and a
+// non-zero IfVersion only an item whose version it is
+type SetOptions struct {
+	Expiry      *time.Time
+	TTL         time.Duration
+	ContentType string
+	Flags       uint32
+	IfAbsent    bool
+	IfVersion   uint64
+}
+
+// Convert the error of a call to one of the Err* values where it stands for one
+func convertError(err error) error {
+	switch status.Code(err) {
+	case codes.NotFound:
+		return ErrNotFound
+	case codes.FailedPrecondition:
+		return ErrConditionFailed
+	}
+	return err
+}
+
+// Get returns the item with the given ID, or ErrNotFound
+func (c *Client) Get(ctx context.Context, id item.ID) (*Item, error) {
+	var res *cachegrpc.GetItemResult
+	err := c.retry(ctx, func(ctx context.Context) (err error) {
+		res, err = c.cache.GetItem(ctx, &cachegrpc.GetItemParams{Owner: id.Owner, Service: id.Service, Name: id.Name})
+		return err
+	})
+	if err != nil {
+		return nil, convertError(err)
+	}
+	return newItem(res.Value, res.Data, res.Expiry, res.Version, res.ContentType, res.Flags), nil
+}
+
+// Build an Item from the fields of a result. A value is returned in data when
+// it is not valid UTF-8
+func newItem(value string, data []byte, expiry *timestamppb.Timestamp, version uint64, contentType string, flags uint32) *Item {
+	it := &Item{Value: value, Version: version, ContentType: contentType, Flags: flags}
+	if len(data) > 0 {
+		it.Value = string(data)
+	}
+	if expiry != nil {
+		exp := expiry.AsTime()
+		it.Expiry = &exp
+	}
+	return it
+}
+
+// Set sets the value of the item with the given ID, and returns its new
+// version. A set with a condition fails with ErrConditionFailed if it does not
+// hold, and is not retried, as it may have taken effect before the failure
+func (c *Client) Set(ctx context.Context, id item.ID, value string, opts SetOptions) (uint64, error) {
+	p := &cachegrpc.SetItemParams{Owner: id.Owner, Service: id.Service, Name: id.Name,
+		ContentType: opts.ContentType, Flags: opts.Flags}
+	if utf8.ValidString(value) {
+		p.Value = value
+	} else {
+		p.Data = []byte(value)
+	}
+	if opts.Expiry != nil {
+		p.Expiry = timestamppb.New(*opts.Expiry)
+	}
+	if opts.TTL != 0 {
+		p.Ttl = durationpb.New(opts.TTL)
+	}
+	call := c.retry
+	switch {
+	case opts.IfAbsent:
+		p.Condition = cachegrpc.SetCondition_IF_ABSENT
+		call = c.once
+	case opts.IfVersion != 0:
+		p.Condition = cachegrpc.SetCondition_IF_VERSION
+		p.Version = opts.IfVersion
+		call = c.once
+	}
+	var res *cachegrpc.SetItemResult
+	err := call(ctx, func(ctx context.Context) (err error) {
+		res, err = c.cache.SetItem(ctx, p)
+		return err
+	})
+	if err != nil {
+		return 0, convertError(err)
+	}
+	return res.Version, nil
+}
+
+// Delete removes the item with the given ID, or returns ErrNotFound. Delete is
+// retried like Get, and an attempt that timed out may have deleted the item
+// without the Client learning of it. So when a retried attempt finds no item,
+// Delete succeeds: the item is gone either way, but whether it was there when
+// Delete was called is then not known
+func (c *Client) Delete(ctx context.Context, id item.ID) error {
+	attempts := 0
+	err := c.retry(ctx, func(ctx context.Context) error {
+		attempts++
+		_, err := c.cache.DeleteItem(ctx, &cachegrpc.GetItemParams{Owner: id.Owner, Service: id.Service, Name: id.Name})
+		if attempts > 1 && status.Code(err) == codes.NotFound {
+			return nil
+		}
+		return err
+	})
+	return convertError(err)
+}
@@ -0,0 +1,252 @@
+package client
+
+import (
+	"context"
+	"net"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/kamenlilovgocourse/gocourse/project/cachegrpc"
+	"github.com/kamenlilovgocourse/gocourse/project/item"
+	"github.com/kamenlilovgocourse/gocourse/project/server"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+// A cache server listening in memory, which can be stopped and started again
+// with the same cache. With auth set, it requires the tokens it issues
+type testServer struct {
+	cache       *server.Cache
+	interceptor grpc.UnaryServerInterceptor
+	auth        bool
+
+	lock sync.Mutex
+	lis  *bufconn.Listener
+	grpc *grpc.Server
+	srv  *server.CacheServer
+}
+
+func (ts *testServer) start() {
+	ts.lock.Lock()
+	defer ts.lock.Unlock()
+	ts.lis = bufconn.Listen(1 << 20)
+	ts.srv = server.NewServer(ts.cache)
+	var unary []grpc.UnaryServerInterceptor
+	if ts.interceptor != nil {
+		unary = append(unary, ts.interceptor)
+	}
+	var opts []grpc.ServerOption
+	if ts.auth {
+		ts.srv.EnableAuth([]byte("secret"))
+		unary = append(unary, ts.srv.UnaryAuthInterceptor)
+		opts = append(opts, grpc.StreamInterceptor(ts.srv.StreamAuthInterceptor))
+	}
+	opts = append(opts, grpc.ChainUnaryInterceptor(unary...))
+	ts.grpc = grpc.NewServer(opts...)
+	cachegrpc.RegisterCacheServerServer(ts.grpc, ts.srv)
+	go ts.grpc.Serve(ts.lis)
+}
+
+func (ts *testServer) stop() {
+	ts.lock.Lock()
+	defer ts.lock.Unlock()
+	ts.srv.Shutdown()
+	ts.grpc.Stop()
+}
+
+func (ts *testServer) dial(ctx context.Context, _ string) (net.Conn, error) {
+	ts.lock.Lock()
+	lis := ts.lis
+	ts.lock.Unlock()
+	return lis.DialContext(ctx)
+}
+
+// Start a server and return a Client of it
+func newTestClient(t *testing.T, ts *testServer) *Client {
+	ts.cache = server.New()
+	t.Cleanup(func() { ts.cache.Close() })
+	ts.start()
+	t.Cleanup(ts.stop)
+	c, err := Dial("bufnet", Options{Backoff: time.Millisecond, DialOptions: []grpc.DialOption{grpc.WithContextDialer(ts.dial)}})
+	if err != nil {
+		t.Fatalf("Dial() returned error %v", err)
+	}
+	t.Cleanup(func() { c.Close() })
+	return c
+}
+
+// Test the typed calls and their errors
+func TestGetSetDelete(t *testing.T) {
+	c := newTestClient(t, &testServer{})
+	ctx := context.Background()
+	id := item.ID{Owner: "o", Service: "s", Name: "n"}
+
+	if _, err := c.Get(ctx, id); err != ErrNotFound {
+		t.Fatalf("Get() of a missing item returned %v, expected ErrNotFound", err)
+	}
+	version, err := c.Set(ctx, id, "\xff\x00", SetOptions{TTL: time.Hour, ContentType: "application/octet-stream"})
+	if err != nil {
+		t.Fatalf("Set() returned error %v", err)
+	}
+	it, err := c.Get(ctx, id)
+	if err != nil {
+		t.Fatalf("Get() returned error %v", err)
+	}
+	if it.Value != "\xff\x00" || it.Version != version || it.ContentType != "application/octet-stream" || it.Expiry == nil || time.Until(*it.Expiry) > time.Hour {
+		t.Fatalf("Get() returned %+v, expected the value just set", it)
+	}
+	if _, err := c.Set(ctx, id, "v", SetOptions{IfAbsent: true}); err != ErrConditionFailed {
+		t.Fatalf("Set() of an existing item with IfAbsent returned %v, expected ErrConditionFailed", err)
+	}
+	if _, err := c.Set(ctx, id, "v", SetOptions{IfVersion: version}); err != nil {
+		t.Fatalf("Set() with the current version returned error %v", err)
+	}
+	if err := c.Delete(ctx, id); err != nil {
+		t.Fatalf("Delete() returned error %v", err)
+	}
+	if err := c.Delete(ctx, id); err != ErrNotFound {
+		t.Fatalf("Delete() of a missing item returned %v, expected ErrNotFound", err)
+	}
+	if _, err := c.Set(ctx, item.ID{Owner: "o"}, "v", SetOptions{}); status.Code(err) != codes.InvalidArgument {
+		t.Fatalf("Set() without a name returned %v, expected InvalidArgument", err)
+	}
+}
+
+// Test that the token returned with the client ID is sent with every call, and
+// that a later Client keeps the ID by passing the token in its Options
+func TestToken(t *testing.T) {
+	ts := &testServer{auth: true}
+	c := newTestClient(t, ts)
+	ctx := context.Background()
+	if _, err := c.Get(ctx, item.ID{Owner: "o", Service: "s", Name: "n"}); status.Code(err) != codes.Unauthenticated {
+		t.Fatalf("Get() without a token returned %v, expected Unauthenticated", err)
+	}
+	owner, err := c.ClientID(ctx)
+	if err != nil || c.Token() == "" {
+		t.Fatalf("ClientID() returned %q, %v with token %q, expected an ID and a token", owner, err, c.Token())
+	}
+	id := item.ID{Owner: owner, Service: "s", Name: "n"}
+	if _, err := c.Set(ctx, id, "v", SetOptions{}); err != nil {
+		t.Fatalf("Set() with the token returned error %v", err)
+	}
+
+	later, err := Dial("bufnet", Options{Token: c.Token(), DialOptions: []grpc.DialOption{grpc.WithContextDialer(ts.dial)}})
+	if err != nil {
+		t.Fatalf("Dial() returned error %v", err)
+	}
+	defer later.Close()
+	if it, err := later.Get(ctx, id); err != nil || it.Value != "v" {
+		t.Fatalf("Get() with the token of an earlier client returned %v, %v, expected value v", it, err)
+	}
+}
+
+// Test that calls that are safe to repeat are retried while the server is
+// unavailable, conditional sets are not, and a retried delete succeeds when an
+// earlier attempt already deleted the item
+func TestRetry(t *testing.T) {
+	var lock sync.Mutex
+	// Calls failing before they are handled, and calls handled whose reply is
+	// replaced by a failure
+	failures, lostReplies := 0, 0
+	ts := &testServer{interceptor: func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		lock.Lock()
+		defer lock.Unlock()
+		if failures > 0 {
+			failures--
+			return nil, status.Error(codes.Unavailable, "try again")
+		}
+		res, err := handler(ctx, req)
+		if lostReplies > 0 {
+			lostReplies--
+			return nil, status.Error(codes.Unavailable, "reply lost")
+		}
+		return res, err
+	}}
+	c := newTestClient(t, ts)
+	ctx := context.Background()
+	id := item.ID{Owner: "o", Service: "s", Name: "n"}
+	fail := func(n int) {
+		lock.Lock()
+		failures = n
+		lock.Unlock()
+	}
+
+	fail(DefaultRetries)
+	if _, err := c.Set(ctx, id, "v", SetOptions{}); err != nil {
+		t.Fatalf("Set() failing %d times returned error %v", DefaultRetries, err)
+	}
+	fail(DefaultRetries + 1)
+	if _, err := c.Get(ctx, id); status.Code(err) != codes.Unavailable {
+		t.Fatalf("Get() failing more often than retried returned %v, expected Unavailable", err)
+	}
+	fail(1)
+	if _, err := c.Set(ctx, id, "w", SetOptions{IfAbsent: true}); status.Code(err) != codes.Unavailable {
+		t.Fatalf("Set() with a condition failing once returned %v, expected Unavailable", err)
+	}
+
+	// The first attempt deletes the item, but the client only sees it fail
+	lock.Lock()
+	lostReplies = 1
+	lock.Unlock()
+	if err := c.Delete(ctx, id); err != nil {
+		t.Fatalf("Delete() retried after deleting the item returned %v", err)
+	}
+	if _, err := c.Get(ctx, id); err != ErrNotFound {
+		t.Fatalf("Get() after Delete() returned %v, expected ErrNotFound", err)
+	}
+	if err := c.Delete(ctx, id); err != ErrNotFound {
+		t.Fatalf("Delete() of a missing item returned %v, expected ErrNotFound", err)
+	}
+}
+
+// Test that a subscription continues where it left off when the server is
+// restarted, without missing or repeating changes
+func TestResubscribe(t *testing.T) {
+	ts := &testServer{}
+	c := newTestClient(t, ts)
+	id := item.ID{Owner: "o", Service: "s", Name: "n"}
+	sub, err := c.Subscribe(context.Background(), id, SubscribeOptions{Initial: true})
+	if err != nil {
+		t.Fatalf("Subscribe() returned error %v", err)
+	}
+	defer sub.Close()
+	next := func() Update {
+		select {
+		case u := <-sub.Updates():
+			return u
+		case <-time.After(5 * time.Second):
+			t.Fatalf("no update received")
+			return Update{}
+		}
+	}
+	if u := next(); u.Event != EventAbsent {
+		t.Fatalf("subscriber received event %v first, expected EventAbsent", u.Event)
+	}
+	ts.cache.Set(id, "1", server.SetOptions{})
+	if u := next(); u.Event != EventUpdated || u.Item.Value != "1" {
+		t.Fatalf("subscriber received %+v, expected value 1", u)
+	}
+
+	ts.stop()
+	ts.cache.Set(id, "2", server.SetOptions{})
+	ts.start()
+	ts.cache.Set(id, "3", server.SetOptions{})
+	for _, want := range []string{"2", "3"} {
+		if u := next(); u.Event != EventUpdated || u.Item.Value != want {
+			t.Fatalf("resubscribed subscriber received %+v, expected value %s", u, want)
+		}
+	}
+
+	sub.Close()
+	for range sub.Updates() {
+	}
+	if err := sub.Err(); err != nil {
+		t.Fatalf("closed subscription ended with error %v", err)
+	}
+	if _, err := c.Subscribe(context.Background(), item.ID{Owner: "o"}, SubscribeOptions{}); status.Code(err) != codes.InvalidArgument {
+		t.Fatalf("Subscribe() without a name returned %v, expected InvalidArgument", err)
+	}
+}
@@ -0,0 +1,224 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/kamenlilovgocourse/gocourse/project/cachegrpc"
+	"github.com/kamenlilovgocourse/gocourse/project/item"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Kind of change delivered to subscribers. The values match cachegrpc.ItemEvent
+type Event int32
+
+const (
+	EventUpdated Event = iota
+	EventDeleted
+	EventExpired
+	EventEvicted
+	// Delivered first to subscriptions asking for the initial value, and again
+	// when a subscription missed changes that the server no longer has: the
+	// current value of the item, or that it has none
+	EventCurrent
+	EventAbsent
+)
+
+// An Update reports a change of a subscribed item. Item holds the new value
+// for EventUpdated and EventCurrent. Seq grows with every change of the item
+type Update struct {
+	ID    item.ID
+	Event Event
+	Item  Item
+	Seq   uint64
+}
+
+// SubscribeOptions control how Subscribe starts. With Initial, the first
+// update is the current value of the item, or that it has none
+type SubscribeOptions struct {
+	Initial bool
+}
+
+// Updates received for a subscription before the Client waits for them to be
+// taken from Updates. While it waits, the server queues them, and deals with a
+// subscriber that does not keep up as it is configured to
+const subscriptionQueue = 64
+
+// A Subscription delivers the changes of an item, or of the items matching a
+// pattern, until it is closed
+type Subscription struct {
+	updates chan Update
+	cancel  context.CancelFunc
+	err     error
+}
+
+// Updates returns the channel delivering the changes, which is closed when the
+// subscription ends
+func (s *Subscription) Updates() <-chan Update {
+	return s.updates
+}
+
+// Err returns why the subscription ended, once Updates is closed. It is nil
+// if it was ended by Close or by its context
+func (s *Subscription) Err() error {
+	return s.err
+}
+
+// Close ends the subscription
+func (s *Subscription) Close() {
+	s.cancel()
+}
+
+// Deliver an update, unless the subscription ends first
+func (s *Subscription) deliver(ctx context.Context, u Update) bool {
+	select {
+	case s.updates <- u:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
+
+// Opens one call of a subscription, and returns the function receiving its
+// updates one by one
+type openFunc func(ctx context.Context, opts ...grpc.CallOption) (func() (Update, error), error)
+
+// Returned when receiving an update to make the call again right away
+var errResubscribe = errors.New("resubscribe")
+
+// Subscribe returns a subscription to the changes of the item with the given
+// ID, whether it exists or not, which lasts until it is closed or ctx ends.
+// When the connection to the server is lost, the subscription is made again
+// once the server can be reached, and continues after the last change it
+// delivered, so that none is missed. If the server no longer has the missed
+// changes, the current value is delivered instead
+func (c *Client) Subscribe(ctx context.Context, id item.ID, opts SubscribeOptions) (*Subscription, error) {
+	// The current value is always asked for, and only delivered with
+	// opts.Initial, so that a subscription that fails does so before returning
+	p := &cachegrpc.SubscribeParams{Owner: id.Owner, Service: id.Service, Name: id.Name, Initial: true}
+	open := func(ctx context.Context, callOpts ...grpc.CallOption) (func() (Update, error), error) {
+		stream, err := c.cache.SubscribeItem(ctx, p, callOpts...)
+		if err != nil {
+			return nil, err
+		}
+		return func() (Update, error) {
+			res, err := stream.Recv()
+			switch {
+			case status.Code(err) == codes.OutOfRange && p.Resume:
+				p.Resume, p.Initial = false, true
+				return Update{}, errResubscribe
+			case err != nil:
+				return Update{}, err
+			}
+			p.Resume, p.Initial, p.FromSequence = true, false, res.Sequence
+			it := newItem(res.Value, res.Data, res.Expiry, res.Version, res.ContentType, res.Flags)
+			return Update{ID: id, Event: Event(res.Event), Item: *it, Seq: res.Sequence}, nil
+		}, nil
+	}
+	return c.subscribe(ctx, open, true, opts.Initial)
+}
+
+// SubscribePattern works like Subscribe, but the owner, service and name of
+// the pattern may contain * and ? wildcards, and the updates are for every
+// matching item the caller may read. Changes made while the connection to the
+// server is lost are not delivered
+func (c *Client) SubscribePattern(ctx context.Context, pattern item.ID) (*Subscription, error) {
+	p := &cachegrpc.GetItemParams{Owner: pattern.Owner, Service: pattern.Service, Name: pattern.Name}
+	open := func(ctx context.Context, callOpts ...grpc.CallOption) (func() (Update, error), error) {
+		stream, err := c.cache.SubscribePattern(ctx, p, callOpts...)
+		if err != nil {
+			return nil, err
+		}
+		return func() (Update, error) {
+			res, err := stream.Recv()
+			if err != nil {
+				return Update{}, err
+			}
+			it := newItem(res.Value, res.Data, res.Expiry, res.Version, res.ContentType, res.Flags)
+			return Update{ID: item.ID{Owner: res.Owner, Service: res.Service, Name: res.Name}, Event: Event(res.Event), Item: *it, Seq: res.Sequence}, nil
+		}, nil
+	}
+	return c.subscribe(ctx, open, false, false)
+}
+
+// Open the first call of a subscription, retrying it like other calls that are
+// safe to repeat, and start receiving its updates. With waitFirst, the first
+// update is received before returning, and delivered if deliverFirst is set
+func (c *Client) subscribe(ctx context.Context, open openFunc, waitFirst, deliverFirst bool) (*Subscription, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	sub := &Subscription{updates: make(chan Update, subscriptionQueue), cancel: cancel}
+	for retry := 0; ; retry++ {
+		// The deadline only applies until the call is open, which then outlives it
+		attempt, cancelAttempt := context.WithCancel(ctx)
+		timer := time.AfterFunc(c.opts.Timeout, cancelAttempt)
+		recv, err := open(attempt)
+		var first Update
+		if err == nil && waitFirst {
+			first, err = recv()
+		}
+		if !timer.Stop() {
+			err = status.Error(codes.DeadlineExceeded, "subscribing took too long")
+		}
+		if err == nil {
+			if deliverFirst {
+				sub.updates <- first
+			}
+			go c.receive(ctx, sub, open, recv, cancelAttempt)
+			return sub, nil
+		}
+		cancelAttempt()
+		if !c.waitRetry(ctx, retry, err) {
+			cancel()
+			return nil, err
+		}
+	}
+}
+
+// Deliver the updates of a subscription until it ends. This function is run
+// in a separate goroutine. When the call fails because the server can not be
+// reached, it is made again once it can, however long that takes. A call that
+// failed needs no cancelling, only the first one, which was opened with a
+// context of its own
+func (c *Client) receive(ctx context.Context, sub *Subscription, open openFunc, recv func() (Update, error), cancelFirst context.CancelFunc) {
+	defer close(sub.updates)
+	defer cancelFirst()
+	retry := 0
+	for {
+		var u Update
+		var err error
+		if recv == nil {
+			recv, err = open(ctx, grpc.WaitForReady(true))
+		}
+		if err == nil {
+			u, err = recv()
+		}
+		if err == nil {
+			retry = 0
+			if !sub.deliver(ctx, u) {
+				return
+			}
+			continue
+		}
+		recv = nil
+		switch {
+		case ctx.Err() != nil:
+			return
+		case err == errResubscribe:
+		case status.Code(err) == codes.Unavailable:
+			t := time.NewTimer(c.backoff(retry))
+			select {
+			case <-t.C:
+			case <-ctx.Done():
+				t.Stop()
+				return
+			}
+			retry++
+		default:
+			sub.err = err
+			return
+		}
+	}
+}
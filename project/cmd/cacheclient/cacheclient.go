@@ -3,24 +3,111 @@ package main
 import (
 	"bufio"
 	"context"
+	"errors"
 	"flag"
 	"fmt"
 	"io"
 	"log"
+	"mime"
+	"net"
 	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
 	"strings"
+	"sync"
+	"time"
 
 	"github.com/kamenlilovgocourse/gocourse/project/cachegrpc"
+	"github.com/kamenlilovgocourse/gocourse/project/certs"
+	"github.com/kamenlilovgocourse/gocourse/project/client"
 	"github.com/kamenlilovgocourse/gocourse/project/item"
-	"google.golang.org/grpc"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
 	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/durationpb"
 	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
 var (
 	serverAddr = flag.String("addr", "localhost:3030", "The server address in the format of host:port")
+	token      = flag.String("token", "", "Token from an earlier session, to keep the same client id on a server that requires authentication")
+	tlsCert    = flag.String("tls-cert", "", "Client certificate file in PEM format, for servers that verify clients")
+	tlsKey     = flag.String("tls-key", "", "Private key file of the client certificate in PEM format")
+	tlsCA      = flag.String("tls-ca", "", "CA certificates in PEM format used to verify the server; the system CAs are used if empty")
+	useTLS     = flag.Bool("tls", false, "Connect with TLS; implied by -tls-cert and -tls-ca")
 )
 
+// Return the transport credentials to dial the server with: TLS if any of the
+// TLS flags is given, plain text otherwise
+func transportCredentials() (credentials.TransportCredentials, error) {
+	if !*useTLS && *tlsCert == "" && *tlsCA == "" {
+		return insecure.NewCredentials(), nil
+	}
+	reloader, err := certs.NewReloader(*tlsCert, *tlsKey, *tlsCA)
+	if err != nil {
+		return nil, err
+	}
+	host, _, err := net.SplitHostPort(*serverAddr)
+	if err != nil {
+		return nil, err
+	}
+	return credentials.NewTLS(reloader.ClientConfig(host)), nil
+}
+
+// Explain an error returned by the server in terms of what the user can do
+// about it, using its status code and, where present, its details
+func describeError(err error) string {
+	st, ok := status.FromError(err)
+	if !ok {
+		return err.Error()
+	}
+	return describeStatus(st.Code(), st.Message(), st.Details())
+}
+
+func describeStatus(code codes.Code, msg string, details []any) string {
+	for _, d := range details {
+		switch d := d.(type) {
+		case *errdetails.ResourceInfo:
+			if code == codes.NotFound {
+				return "Item " + d.ResourceName + " does not exist"
+			}
+		case *errdetails.BadRequest:
+			if len(d.FieldViolations) > 0 {
+				return "Invalid " + d.FieldViolations[0].Field + ", " + msg
+			}
+		case *errdetails.ErrorInfo:
+			switch d.Reason {
+			case "NOT_ADMIN":
+				return "Permission denied: only the clients the server was started with in -admin-clients may do this"
+			case "SLOW_SUBSCRIBER":
+				return "Dropped by the server for not keeping up with the changes: " + msg
+			}
+		}
+	}
+	switch code {
+	case codes.NotFound:
+		return "Not found: " + msg
+	case codes.InvalidArgument, codes.OutOfRange:
+		return "Invalid request, " + msg
+	case codes.FailedPrecondition:
+		return "Not done, " + msg
+	case codes.PermissionDenied:
+		return "Permission denied: " + msg + ". The owner must grant you access first"
+	case codes.Unauthenticated:
+		return "Not authenticated: " + msg + ". Restart without -token, or with the one printed at startup"
+	case codes.ResourceExhausted:
+		return "Server is out of room: " + msg
+	case codes.Unavailable:
+		return "Server is not available: " + msg
+	case codes.DeadlineExceeded:
+		return "Server did not answer in time"
+	}
+	return "Error from service: " + code.String() + ", " + msg
+}
+
 // Parse a command input via bufio.NewReader.ReadString, truncate any trailing cr and lf,
 // and return the first word (the command name) and the second part (the command string)
 func parseCommand(input string) (iCmd, iParam string) {
@@ -44,36 +131,498 @@ func parseCommand(input string) (iCmd, iParam string) {
 	return iCmd, iParam
 }
 
-// Handle the 'subscribe' user command. This function is run in a separate goroutine.
-// It will issue a SubscribeItem gRPC call passing the server an item.ID obtained
-// from the console, and will repeatedly listen on the formed stream and print
-// any received subscriptions on the console
-func subscribeListener(client cachegrpc.CacheServerClient, id item.ID) {
-	ip := cachegrpc.GetItemParams{Owner: id.Owner, Service: id.Service, Name: id.Name}
-	stream, err1 := client.SubscribeItem(context.Background(), &ip)
-	if err1 != nil {
-		fmt.Printf("Error subscribing to %s: %v\n", id.Compose(), err1)
+// The subscriptions of the console by the item ID or pattern they were made
+// for. They are all watches on a single Watch stream, which is opened with the
+// first of them, so that the unsubscribe command can end one by removing its
+// watch
+type watcher struct {
+	client cachegrpc.CacheServerClient
+
+	lock     sync.Mutex
+	stream   cachegrpc.CacheServer_WatchClient
+	cancel   context.CancelFunc
+	retrying bool
+	nextID   uint64
+	byKey    map[string]*watch
+	byID     map[uint64]*watch
+}
+
+type watch struct {
+	id      uint64
+	command string
+	target  item.ID
+	pattern bool
+	// Whether the watch of an item has received a change, and the sequence
+	// number of the last one, to resume after
+	resume bool
+	seq    uint64
+}
+
+func newWatcher(client cachegrpc.CacheServerClient) *watcher {
+	return &watcher{client: client, byKey: make(map[string]*watch), byID: make(map[uint64]*watch)}
+}
+
+// How often, and how long apart, the watcher tries to reopen a Watch stream
+// that was interrupted
+const (
+	resumeAttempts = 5
+	resumeDelay    = time.Second
+)
+
+// Handle the 'subscribe' and 'psubscribe' user commands, by starting a watch of
+// the item or pattern, and return false if there already is one for the ID.
+// The current value of an item is printed first, then every received change
+func (w *watcher) add(target item.ID, command string) bool {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+	if _, found := w.byKey[target.Compose()]; found {
+		return false
+	}
+	w.nextID++
+	wt := &watch{id: w.nextID, command: command, target: target, pattern: command == "psubscribe"}
+	w.byKey[target.Compose()] = wt
+	w.byID[wt.id] = wt
+	switch {
+	case w.retrying:
+		// Started when the stream is reopened
+	case w.stream == nil:
+		stream, err := w.open()
+		if err != nil {
+			fmt.Printf("Error subscribing to %s: %s\n", target.Compose(), describeError(err))
+			delete(w.byKey, target.Compose())
+			delete(w.byID, wt.id)
+			return true
+		}
+		go w.receive(stream)
+	default:
+		w.start(wt)
+	}
+	return true
+}
+
+// End the subscription for an ID, and return false if there is none
+func (w *watcher) remove(target item.ID) bool {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+	wt, found := w.byKey[target.Compose()]
+	if !found {
+		return false
+	}
+	delete(w.byKey, target.Compose())
+	delete(w.byID, wt.id)
+	if w.stream != nil {
+		w.stream.Send(&cachegrpc.WatchRequest{WatchId: wt.id, Action: cachegrpc.WatchAction_UNWATCH})
+	}
+	return true
+}
+
+// Return the active subscriptions, as the commands that made them, sorted
+func (w *watcher) list() []string {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+	var res []string
+	for key, wt := range w.byKey {
+		res = append(res, wt.command+" "+key)
+	}
+	sort.Strings(res)
+	return res
+}
+
+// Open the Watch stream and start every watch on it. Called with the lock held
+func (w *watcher) open() (cachegrpc.CacheServer_WatchClient, error) {
+	ctx, cancel := context.WithCancel(context.Background())
+	stream, err := w.client.Watch(ctx)
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+	w.stream, w.cancel = stream, cancel
+	for _, wt := range w.byID {
+		w.start(wt)
+	}
+	return stream, nil
+}
+
+// Send the request starting a watch. A watch of an item that already received
+// changes resumes after the last of them, otherwise it starts with the current
+// value. Called with the lock held. Errors are not returned, as the stream
+// receiving them breaks off too, and then all watches are started again
+func (w *watcher) start(wt *watch) {
+	req := &cachegrpc.WatchRequest{WatchId: wt.id, Owner: wt.target.Owner, Service: wt.target.Service, Name: wt.target.Name}
+	if wt.pattern {
+		req.Action = cachegrpc.WatchAction_WATCH_PATTERN
+	} else {
+		req.Action = cachegrpc.WatchAction_WATCH_ITEM
+		req.Initial = !wt.resume
+		req.Resume = wt.resume
+		req.FromSequence = wt.seq
+	}
+	w.stream.Send(req)
+}
+
+// Receive and print the events of all watches. This function is run in a
+// separate goroutine for as long as there is a stream. If the stream is
+// interrupted, for example because the server restarts, it is reopened, so
+// that no change is missed
+func (w *watcher) receive(stream cachegrpc.CacheServer_WatchClient) {
+	attempts := 0
+	for stream != nil {
+		ev, err := stream.Recv()
+		if err != nil {
+			stream = w.reopen(err, &attempts)
+			continue
+		}
+		attempts = 0
+		w.dispatch(ev)
+	}
+}
+
+// Replace a stream that failed with err, after a delay. Return nil, ending all
+// watches, if it can not be replaced, or if there are no watches left
+func (w *watcher) reopen(err error, attempts *int) cachegrpc.CacheServer_WatchClient {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+	w.cancel()
+	w.stream = nil
+	for len(w.byID) > 0 {
+		if status.Code(err) != codes.Unavailable || *attempts >= resumeAttempts {
+			fmt.Printf("Subscriptions ended: %s\n", describeError(err))
+			w.byKey = make(map[string]*watch)
+			w.byID = make(map[uint64]*watch)
+			break
+		}
+		*attempts++
+		fmt.Printf("Subscriptions interrupted, resuming in %v: %s\n", resumeDelay, describeError(err))
+		w.retrying = true
+		w.lock.Unlock()
+		time.Sleep(resumeDelay)
+		w.lock.Lock()
+		w.retrying = false
+		if len(w.byID) == 0 {
+			break
+		}
+		var stream cachegrpc.CacheServer_WatchClient
+		if stream, err = w.open(); err == nil {
+			return stream
+		}
+	}
+	return nil
+}
+
+// Print an event of the Watch stream, and keep track of the watch it is for
+func (w *watcher) dispatch(ev *cachegrpc.WatchEvent) {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+	wt, found := w.byID[ev.WatchId]
+	if !found {
+		// A watch removed by the unsubscribe command
 		return
 	}
-	for {
-		res, err := stream.Recv()
-		if err == io.EOF {
-			return
+	switch {
+	case ev.Change != nil:
+		if !wt.pattern {
+			wt.resume = true
+			wt.seq = ev.Change.Sequence
+		}
+		printChange(wt, ev.Change)
+	case codes.Code(ev.Code) == codes.OutOfRange && wt.resume:
+		// The server no longer has the changes we missed, start over from the
+		// current value
+		fmt.Printf("Missed changes of %s are no longer kept, getting the current value\n", wt.target.Compose())
+		wt.resume = false
+		w.start(wt)
+	case ev.Code != 0 || ev.Ended:
+		if ev.Code != 0 {
+			fmt.Printf("Subscription to %s ended: %s\n", wt.target.Compose(), describeStatus(codes.Code(ev.Code), ev.Error, nil))
 		}
+		if ev.Ended {
+			delete(w.byKey, wt.target.Compose())
+			delete(w.byID, wt.id)
+		}
+	}
+}
+
+// Print a change received by a watch. The changes of pattern watches carry the
+// ID of the changed item
+func printChange(wt *watch, res *cachegrpc.PatternEvent) {
+	id := item.ID{Owner: res.Owner, Service: res.Service, Name: res.Name}
+	prefix := fmt.Sprintf("Received sub for %s", id.Compose())
+	if wt.pattern {
+		prefix = fmt.Sprintf("Received psub %s for %s", wt.target.Compose(), id.Compose())
+	}
+	switch res.Event {
+	case cachegrpc.ItemEvent_DELETED:
+		fmt.Printf("%s: item deleted\n", prefix)
+	case cachegrpc.ItemEvent_EXPIRED:
+		fmt.Printf("%s: item expired\n", prefix)
+	case cachegrpc.ItemEvent_EVICTED:
+		fmt.Printf("%s: item evicted\n", prefix)
+	case cachegrpc.ItemEvent_ABSENT:
+		fmt.Printf("Subscribed to %s: item has no value\n", id.Compose())
+	case cachegrpc.ItemEvent_CURRENT:
+		fmt.Printf("Subscribed to %s: current value %s\n", id.Compose(), formatValue(res.Value, res.Data, res.ContentType, res.Flags))
+	default:
+		fmt.Printf("%s: new value %s\n", prefix, formatValue(res.Value, res.Data, res.ContentType, res.Flags))
+	}
+}
+
+// Format a value received from the server for the console. Values that are not
+// text are only summarized; getfile saves them to a file
+func formatValue(value string, data []byte, contentType string, flags uint32) string {
+	s := value
+	if len(data) > 0 {
+		s = fmt.Sprintf("<%d bytes of binary data>", len(data))
+	}
+	if contentType != "" {
+		s += " [" + contentType + "]"
+	}
+	if flags != 0 {
+		s += fmt.Sprintf(" flags %d", flags)
+	}
+	return s
+}
+
+// Build the SetItem parameters for an assignment parsed from the console. The
+// expiry is sent as a time to live, so that the server computes when the item
+// expires with its own clock
+func setParams(iassn item.Assignment) *cachegrpc.SetItemParams {
+	ip := cachegrpc.SetItemParams{Owner: iassn.Id.Owner, Service: iassn.Id.Service, Name: iassn.Id.Name, Value: iassn.Value}
+	if iassn.Expiry != nil {
+		ip.Ttl = durationpb.New(iassn.TTL)
+	}
+	return &ip
+}
+
+// Parse the parameter of the touch and gat commands: an item ID, optionally
+// followed by the new time to live in seconds
+func parseTouch(iParam string) (*cachegrpc.TouchParams, error) {
+	fields := strings.Fields(iParam)
+	if len(fields) == 0 || len(fields) > 2 {
+		return nil, errors.New("incorrect touch " + iParam)
+	}
+	iassn := item.ID{}
+	if err := iassn.Parse(fields[0]); err != nil {
+		return nil, err
+	}
+	ip := &cachegrpc.TouchParams{Owner: iassn.Owner, Service: iassn.Service, Name: iassn.Name}
+	if len(fields) > 1 {
+		expSeconds, err := strconv.Atoi(fields[1])
 		if err != nil {
-			fmt.Printf("Error while receiving subscription: %v\n", err)
-			return
+			return nil, errors.New("incorrect expiry " + fields[1])
+		}
+		ip.Ttl = durationpb.New(time.Second * time.Duration(expSeconds))
+	}
+	return ip, nil
+}
+
+// Describe the expiry of an item for the console
+func formatExpiry(expiry *timestamppb.Timestamp) string {
+	if expiry == nil {
+		return "no expiry"
+	}
+	return "expires at " + expiry.AsTime().Local().Format(time.RFC3339)
+}
+
+// Parse the parameter of the setfile command: an assignment whose value is the
+// name of the file to read the value from, optionally followed by the content
+// type and the flags. Without a content type, it is guessed from the file name
+func parseSetFile(iParam string) (*cachegrpc.SetItemParams, error) {
+	fields := strings.Fields(iParam)
+	if len(fields) < 1 || len(fields) > 3 {
+		return nil, errors.New("incorrect setfile " + iParam)
+	}
+	iassn, err := item.ParseAssignment(fields[0])
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(iassn.Value)
+	if err != nil {
+		return nil, err
+	}
+	ip := setParams(iassn)
+	ip.Value = ""
+	ip.Data = data
+	ip.ContentType = mime.TypeByExtension(filepath.Ext(iassn.Value))
+	if len(fields) > 1 {
+		ip.ContentType = fields[1]
+	}
+	if len(fields) > 2 {
+		flags, err := strconv.ParseUint(fields[2], 10, 32)
+		if err != nil {
+			return nil, errors.New("incorrect flags " + fields[2])
+		}
+		ip.Flags = uint32(flags)
+	}
+	return ip, nil
+}
+
+// Split the parameter of the cas command into the assignment and the trailing
+// condition, and fill in the SetItem condition. The condition is either a version
+// number as returned by get, or =value to compare against the current value
+func parseCas(iParam string) (*cachegrpc.SetItemParams, error) {
+	condIndex := strings.LastIndex(iParam, " ")
+	if condIndex < 0 {
+		return nil, errors.New("missing version or =value in " + iParam)
+	}
+	iassn, err := item.ParseAssignment(strings.TrimSpace(iParam[:condIndex]))
+	if err != nil {
+		return nil, err
+	}
+	ip := setParams(iassn)
+	cond := iParam[condIndex+1:]
+	if strings.HasPrefix(cond, "=") {
+		ip.Condition = cachegrpc.SetCondition_IF_VALUE
+		ip.ExpectedValue = cond[1:]
+		return ip, nil
+	}
+	version, err := strconv.ParseUint(cond, 10, 64)
+	if err != nil {
+		return nil, errors.New("incorrect version " + cond)
+	}
+	ip.Condition = cachegrpc.SetCondition_IF_VERSION
+	ip.Version = version
+	return ip, nil
+}
+
+// Parse the parameter of the incr and decr commands: an item ID, followed by an
+// optional delta (1 by default) and an optional expiry in seconds, used if the
+// item has to be created. Missing items are created with value 0. For decr the
+// delta is negated
+func parseIncrement(iParam string, negate bool) (*cachegrpc.IncrementParams, error) {
+	fields := strings.Fields(iParam)
+	if len(fields) == 0 || len(fields) > 3 {
+		return nil, errors.New("incorrect increment " + iParam)
+	}
+	iassn := item.ID{}
+	if err := iassn.Parse(fields[0]); err != nil {
+		return nil, err
+	}
+	ip := &cachegrpc.IncrementParams{Owner: iassn.Owner, Service: iassn.Service, Name: iassn.Name, Delta: 1, Create: true}
+	if len(fields) > 1 {
+		delta, err := strconv.ParseInt(fields[1], 10, 64)
+		if err != nil {
+			return nil, errors.New("incorrect delta " + fields[1])
+		}
+		ip.Delta = delta
+	}
+	if len(fields) > 2 {
+		expSeconds, err := strconv.Atoi(fields[2])
+		if err != nil {
+			return nil, errors.New("incorrect expiry " + fields[2])
+		}
+		ip.Ttl = durationpb.New(time.Second * time.Duration(expSeconds))
+	}
+	if negate {
+		ip.Delta = -ip.Delta
+	}
+	return ip, nil
+}
+
+// Parse the parameter of the grant command: a service, the client id to share it
+// with (or * for everyone) and the access to give, one of r, w, rw or none
+func parseGrant(iParam string) (*cachegrpc.GrantParams, error) {
+	fields := strings.Fields(iParam)
+	if len(fields) != 3 {
+		return nil, errors.New("incorrect grant " + iParam)
+	}
+	ip := &cachegrpc.GrantParams{Service: fields[0], Grantee: fields[1]}
+	switch fields[2] {
+	case "r":
+		ip.Read = true
+	case "w", "rw":
+		ip.Read = true
+		ip.Write = true
+	case "none":
+	default:
+		return nil, errors.New("incorrect access " + fields[2])
+	}
+	return ip, nil
+}
+
+// Parse the parameter of the flush command: nothing to flush every item, an owner
+// to flush its items, or owner:service to flush the items of one service. Returns
+// which of the Admin calls to make
+func parseFlush(iParam string) (string, *cachegrpc.FlushParams, error) {
+	fields := strings.Fields(iParam)
+	switch {
+	case len(fields) == 0:
+		return "all", &cachegrpc.FlushParams{}, nil
+	case len(fields) > 1:
+		return "", nil, errors.New("incorrect flush " + iParam)
+	}
+	owner, service, found := strings.Cut(fields[0], ":")
+	if !found {
+		return "owner", &cachegrpc.FlushParams{Owner: owner}, nil
+	}
+	if service == "" || strings.Contains(service, ":") {
+		return "", nil, errors.New("incorrect flush " + iParam)
+	}
+	return "service", &cachegrpc.FlushParams{Owner: owner, Service: service}, nil
+}
+
+// Print the statistics returned by the Admin Stats call. Shards are summarized
+// by how evenly the items are spread over them, and only the subscriptions
+// that are behind are listed
+func printStats(st *cachegrpc.StatsResult) {
+	fmt.Printf("Uptime: %v\n", st.Uptime.AsDuration().Round(time.Second))
+	fmt.Printf("Items: %d, estimated memory %d bytes\n", st.Items, st.MemoryBytes)
+	fmt.Printf("Evicted: %d, expired: %d, waiting to expire: %d\n", st.Evictions, st.Expirations, st.PendingExpiries)
+	fmt.Printf("Subscriptions: %d, connected clients: %d\n", st.Subscriptions, st.ConnectedClients)
+	fmt.Printf("Subscriber lag: at most %d updates, %d updates dropped, %d slow subscribers disconnected\n", st.SubscriberLagMax, st.DroppedUpdates, st.SlowDisconnects)
+	for _, sub := range st.Subscribers {
+		if sub.Lag == 0 && sub.DroppedUpdates == 0 {
+			continue
 		}
-		fmt.Printf("Received sub for %s: new value %s\n", id.Compose(), res.Value)
+		kind := "item"
+		if sub.Pattern {
+			kind = "pattern"
+		}
+		fmt.Printf("  %s %s:%s:%s: %d updates waiting, %d dropped\n", kind, sub.Owner, sub.Service, sub.Name, sub.Lag, sub.DroppedUpdates)
+	}
+	if len(st.Shards) == 0 {
+		return
 	}
+	least, most, empty := st.Shards[0].Items, st.Shards[0].Items, 0
+	for _, shard := range st.Shards {
+		if shard.Items < least {
+			least = shard.Items
+		}
+		if shard.Items > most {
+			most = shard.Items
+		}
+		if shard.Items == 0 {
+			empty++
+		}
+	}
+	fmt.Printf("Shards: %d, items per shard %d to %d, %d empty\n", len(st.Shards), least, most, empty)
 }
 
 // Display help on the available commands for the command line client
 func commandHelp() {
 	fmt.Println("\nAvailable commands:")
 	fmt.Println("set user:service:item=value,expiry sets an item in the cache")
+	fmt.Println("add user:service:item=value,expiry sets an item only if it is not present yet")
+	fmt.Println("cas user:service:item=value,expiry version sets an item only if its version matches")
+	fmt.Println("cas user:service:item=value,expiry =oldvalue sets an item only if its value matches")
 	fmt.Println("get user:service:item retrieves an item from the cache")
+	fmt.Println("setfile user:service:item=file,expiry content-type flags sets an item to the contents of a file")
+	fmt.Println("getfile user:service:item file writes the value of an item to a file")
+	fmt.Println("incr user:service:item delta expiry adds delta (default 1) to a counter, creating it if needed")
+	fmt.Println("decr user:service:item delta expiry subtracts delta (default 1) from a counter, creating it if needed")
+	fmt.Println("touch user:service:item expiry sets the expiry of an item in seconds from now, or removes it")
+	fmt.Println("gat user:service:item expiry retrieves an item and sets its expiry in seconds from now")
+	fmt.Println("ttl user:service:item shows how long an item has left to live")
 	fmt.Println("subscribe user:service:item subscribes for updates to a shared cached item")
+	fmt.Println("psubscribe user:service:pattern subscribes for updates to all items matching a pattern with * and ?")
+	fmt.Println("unsubscribe user:service:item ends a subscription made by subscribe or psubscribe")
+	fmt.Println("subscriptions lists the active subscriptions")
+	fmt.Println("delete user:service:item removes an item from the cache")
+	fmt.Println("mget user:service:item ... retrieves several space separated items in one call")
+	fmt.Println("mset user:service:item=value,expiry ... sets several space separated items in one call")
+	fmt.Println("grant service clientid r|rw|none shares your items of a service with another client, or * for all")
+	fmt.Println("stats shows statistics of the server")
+	fmt.Println("flush user:service removes all items of a service, or of a user without :service, or all items without either")
+	fmt.Println("keys prefix lists the items whose user:service:item starts with prefix, or all items without it")
 	fmt.Println("quit quits the client")
 }
 
@@ -83,26 +632,45 @@ func main() {
 	fmt.Printf("cacheclient seeking server at %s\n", *serverAddr)
 
 	// Contact the server
-	var opts []grpc.DialOption
-	opts = append(opts, grpc.WithTransportCredentials(insecure.NewCredentials()))
-	conn, err := grpc.Dial(*serverAddr, opts...)
+	creds, err := transportCredentials()
+	if err != nil {
+		log.Fatalf("failed to set up TLS: %v", err)
+	}
+	cc, err := client.Dial(*serverAddr, client.Options{Credentials: creds, Token: *token})
 	if err != nil {
 		log.Fatalf("fail to dial: %v", err)
 	}
-	defer conn.Close()
+	defer cc.Close()
+	conn := cc.Conn()
 	client := cachegrpc.NewCacheServerClient(conn)
+	admin := cachegrpc.NewAdminClient(conn)
 
 	// Issue a GetClientID call and just display the received value on the
 	// console. The user is not obligated to use this value as the owner name
 	// in set, get and subscribe calls, but it's a good practice to keep your
-	// own private ID in a multiuser environment
+	// own private ID in a multiuser environment. If the server requires
+	// authentication, the owner must be this ID, and the token that comes with
+	// it is sent with every further call. A token from an earlier session can
+	// be passed with -token to keep using the same ID
 	ctx := context.Background()
-	clientID, err := client.GetClientID(ctx, &cachegrpc.AssignClientID{})
-	if err != nil {
-		log.Fatalf("client.GetClientID failed: %v", err)
+	if *token != "" {
+		id := *token
+		if dot := strings.LastIndex(id, "."); dot >= 0 {
+			id = id[:dot]
+		}
+		fmt.Printf("Using client id %s from the token\n", id)
+	} else {
+		clientID, err := cc.ClientID(ctx)
+		if err != nil {
+			log.Fatalf("client.ClientID failed: %v", err)
+		}
+		fmt.Printf("Server assigned us client id %s\n", clientID)
+		if cc.Token() != "" {
+			fmt.Printf("Pass -token %s to keep this id in a later session\n", cc.Token())
+		}
 	}
-	fmt.Printf("Server assigned us client id %s\n", clientID.Id)
 
+	subs := newWatcher(client)
 	linereader := bufio.NewReader(os.Stdin)
 	commandHelp()
 	for {
@@ -125,13 +693,41 @@ func main() {
 				fmt.Println("Error in expression: ", err)
 				continue
 			}
-			ip := cachegrpc.SetItemParams{Owner: iassn.Id.Owner, Service: iassn.Id.Service, Name: iassn.Id.Name, Value: iassn.Value}
-			if iassn.Expiry == nil {
-				ip.Expiry = nil
-			} else {
-				ip.Expiry = timestamppb.New(*iassn.Expiry)
+			if _, err2 := client.SetItem(ctx, setParams(iassn)); err2 != nil {
+				fmt.Println(describeError(err2))
+				continue
 			}
-			client.SetItem(ctx, &ip)
+
+		case iCmd == "add":
+			// The add command is a set that only succeeds if the item does not exist yet
+			iassn, err := item.ParseAssignment(iParam)
+			if err != nil {
+				fmt.Println("Error in expression: ", err)
+				continue
+			}
+			ip := setParams(iassn)
+			ip.Condition = cachegrpc.SetCondition_IF_ABSENT
+			res, err2 := client.SetItem(ctx, ip)
+			if err2 != nil {
+				fmt.Println(describeError(err2))
+				continue
+			}
+			fmt.Printf("Added with version %d\n", res.Version)
+
+		case iCmd == "cas":
+			// The cas command is a set that only succeeds if the item's current version,
+			// or value, matches the one given after the assignment
+			ip, err := parseCas(iParam)
+			if err != nil {
+				fmt.Println("Error in expression: ", err)
+				continue
+			}
+			res, err2 := client.SetItem(ctx, ip)
+			if err2 != nil {
+				fmt.Println(describeError(err2))
+				continue
+			}
+			fmt.Printf("Set with version %d\n", res.Version)
 
 		case iCmd == "get":
 			// The get command accepts an item ID as its parameter. Parse it out, then
@@ -145,21 +741,308 @@ func main() {
 			ip := cachegrpc.GetItemParams{Owner: iassn.Owner, Service: iassn.Service, Name: iassn.Name}
 			ipres, err2 := client.GetItem(ctx, &ip)
 			if err2 != nil {
-				fmt.Println("Error from service: ", err2)
+				fmt.Println(describeError(err2))
+				continue
+			}
+			fmt.Printf("Result: %s (version %d)\n", formatValue(ipres.Value, ipres.Data, ipres.ContentType, ipres.Flags), ipres.Version)
+
+		case iCmd == "setfile":
+			// The setfile command sets an item to the contents of a file, which may
+			// hold binary data
+			ip, err := parseSetFile(iParam)
+			if err != nil {
+				fmt.Println("Error in expression: ", err)
+				continue
+			}
+			res, err2 := client.SetItem(ctx, ip)
+			if err2 != nil {
+				fmt.Println(describeError(err2))
+				continue
+			}
+			fmt.Printf("Set %d bytes with version %d\n", len(ip.Data), res.Version)
+
+		case iCmd == "getfile":
+			// The getfile command writes the value of an item to a file, so that
+			// binary values can be retrieved
+			fields := strings.Fields(iParam)
+			iassn := item.ID{}
+			if len(fields) != 2 {
+				fmt.Println("Error in expression: ", "incorrect getfile "+iParam)
+				continue
+			}
+			if err := iassn.Parse(fields[0]); err != nil {
+				fmt.Println("Error in expression: ", err)
+				continue
+			}
+			ip := cachegrpc.GetItemParams{Owner: iassn.Owner, Service: iassn.Service, Name: iassn.Name}
+			ipres, err2 := client.GetItem(ctx, &ip)
+			if err2 != nil {
+				fmt.Println(describeError(err2))
+				continue
+			}
+			data := ipres.Data
+			if len(data) == 0 {
+				data = []byte(ipres.Value)
+			}
+			if err := os.WriteFile(fields[1], data, 0644); err != nil {
+				fmt.Println("Error writing file: ", err)
+				continue
+			}
+			fmt.Printf("Wrote %d bytes to %s (version %d)\n", len(data), fields[1], ipres.Version)
+
+		case iCmd == "touch":
+			// The touch command changes the expiry of an item, or removes it if no
+			// expiry is given, without changing the value
+			ip, err := parseTouch(iParam)
+			if err != nil {
+				fmt.Println("Error in expression: ", err)
+				continue
+			}
+			ipres, err2 := client.Touch(ctx, ip)
+			if err2 != nil {
+				fmt.Println(describeError(err2))
+				continue
+			}
+			fmt.Printf("Touched, %s (version %d)\n", formatExpiry(ipres.Expiry), ipres.Version)
+
+		case iCmd == "gat":
+			// The gat command retrieves an item and changes its expiry as touch does,
+			// but the server refuses it without an expiry
+			ip, err := parseTouch(iParam)
+			if err != nil {
+				fmt.Println("Error in expression: ", err)
+				continue
+			}
+			ipres, err2 := client.GetAndTouch(ctx, ip)
+			if err2 != nil {
+				fmt.Println(describeError(err2))
+				continue
+			}
+			fmt.Printf("Result: %s (version %d, %s)\n", formatValue(ipres.Value, ipres.Data, ipres.ContentType, ipres.Flags), ipres.Version, formatExpiry(ipres.Expiry))
+
+		case iCmd == "ttl":
+			// The ttl command displays how long an item has left to live
+			iassn := item.ID{}
+			if err := iassn.Parse(iParam); err != nil {
+				fmt.Println("Error in expression: ", err)
+				continue
+			}
+			ip := cachegrpc.GetItemParams{Owner: iassn.Owner, Service: iassn.Service, Name: iassn.Name}
+			ipres, err2 := client.TTL(ctx, &ip)
+			if err2 != nil {
+				fmt.Println(describeError(err2))
+				continue
+			}
+			if !ipres.HasExpiry {
+				fmt.Println("Item does not expire")
+				continue
+			}
+			fmt.Printf("Item expires in %v\n", ipres.Ttl.AsDuration().Round(time.Millisecond))
+
+		case iCmd == "incr" || iCmd == "decr":
+			// The incr and decr commands atomically change a counter on the server and
+			// print its new value
+			ip, err := parseIncrement(iParam, iCmd == "decr")
+			if err != nil {
+				fmt.Println("Error in expression: ", err)
+				continue
+			}
+			ipres, err2 := client.Increment(ctx, ip)
+			if err2 != nil {
+				fmt.Println(describeError(err2))
 				continue
 			}
-			fmt.Printf("Result: %s\n", ipres.Value)
+			fmt.Printf("Result: %d (version %d)\n", ipres.Value, ipres.Version)
 
 		case iCmd == "subscribe":
 			// The subscribe command accepts an item ID as its parameter. Parse it out, then
-			// spawn a goroutine to perform asynchronous listening to the formed stream request
+			// add a watch of the item to the Watch stream
 			iassn := item.ID{}
 			err := iassn.Parse(iParam)
 			if err != nil {
 				fmt.Println("Error in expression: ", err)
 				continue
 			}
-			go subscribeListener(client, iassn)
+			if !subs.add(iassn, iCmd) {
+				fmt.Printf("Already subscribed to %s\n", iassn.Compose())
+			}
+
+		case iCmd == "psubscribe":
+			// The psubscribe command accepts an item ID with wildcards as its parameter,
+			// and adds a watch of every matching item to the Watch stream
+			iassn := item.ID{}
+			err := iassn.Parse(iParam)
+			if err != nil {
+				fmt.Println("Error in expression: ", err)
+				continue
+			}
+			if !subs.add(iassn, iCmd) {
+				fmt.Printf("Already subscribed to %s\n", iassn.Compose())
+			}
+
+		case iCmd == "unsubscribe":
+			// The unsubscribe command ends the subscription made by subscribe or
+			// psubscribe with the same parameter
+			iassn := item.ID{}
+			err := iassn.Parse(iParam)
+			if err != nil {
+				fmt.Println("Error in expression: ", err)
+				continue
+			}
+			if !subs.remove(iassn) {
+				fmt.Printf("Not subscribed to %s\n", iassn.Compose())
+			}
+
+		case iCmd == "subscriptions":
+			// The subscriptions command lists the active subscriptions
+			active := subs.list()
+			for _, sub := range active {
+				fmt.Println(sub)
+			}
+			fmt.Printf("%d subscriptions\n", len(active))
+
+		case iCmd == "delete":
+			// The delete command accepts an item ID as its parameter. Parse it out, then
+			// call the server to remove the item
+			iassn := item.ID{}
+			err := iassn.Parse(iParam)
+			if err != nil {
+				fmt.Println("Error in expression: ", err)
+				continue
+			}
+			ip := cachegrpc.GetItemParams{Owner: iassn.Owner, Service: iassn.Service, Name: iassn.Name}
+			_, err2 := client.DeleteItem(ctx, &ip)
+			if err2 != nil {
+				fmt.Println(describeError(err2))
+				continue
+			}
+
+		case iCmd == "mget":
+			// The mget command accepts a space separated list of item IDs. Parse them all,
+			// then retrieve them with a single call and print each result
+			ip := cachegrpc.MultiGetParams{}
+			ids := []item.ID{}
+			var err error
+			for _, param := range strings.Fields(iParam) {
+				iassn := item.ID{}
+				if err = iassn.Parse(param); err != nil {
+					break
+				}
+				ids = append(ids, iassn)
+				ip.Items = append(ip.Items, &cachegrpc.GetItemParams{Owner: iassn.Owner, Service: iassn.Service, Name: iassn.Name})
+			}
+			if err != nil {
+				fmt.Println("Error in expression: ", err)
+				continue
+			}
+			ipres, err2 := client.MultiGet(ctx, &ip)
+			if err2 != nil {
+				fmt.Println(describeError(err2))
+				continue
+			}
+			for i, res := range ipres.Items {
+				if res.Error != "" {
+					fmt.Printf("%s: %s\n", ids[i].Compose(), describeStatus(codes.Code(res.Code), res.Error, nil))
+					continue
+				}
+				fmt.Printf("%s: %s (version %d)\n", ids[i].Compose(), formatValue(res.Result.Value, res.Result.Data, res.Result.ContentType, res.Result.Flags), res.Result.Version)
+			}
+
+		case iCmd == "mset":
+			// The mset command accepts a space separated list of assignments, which are
+			// all sent to the server in a single call
+			ip := cachegrpc.MultiSetParams{}
+			var err error
+			for _, param := range strings.Fields(iParam) {
+				var iassn item.Assignment
+				if iassn, err = item.ParseAssignment(param); err != nil {
+					break
+				}
+				ip.Items = append(ip.Items, setParams(iassn))
+			}
+			if err != nil {
+				fmt.Println("Error in expression: ", err)
+				continue
+			}
+			ipres, err2 := client.MultiSet(ctx, &ip)
+			if err2 != nil {
+				fmt.Println(describeError(err2))
+				continue
+			}
+			for i, res := range ipres.Items {
+				id := item.ID{Owner: ip.Items[i].Owner, Service: ip.Items[i].Service, Name: ip.Items[i].Name}
+				if res.Error != "" {
+					fmt.Printf("%s: %s\n", id.Compose(), describeStatus(codes.Code(res.Code), res.Error, nil))
+				}
+			}
+
+		case iCmd == "grant":
+			// The grant command shares the items of one of our services with another client
+			ip, err := parseGrant(iParam)
+			if err != nil {
+				fmt.Println("Error in expression: ", err)
+				continue
+			}
+			if _, err2 := client.Grant(ctx, ip); err2 != nil {
+				fmt.Println(describeError(err2))
+				continue
+			}
+
+		case iCmd == "stats":
+			// The stats command displays the statistics of the server
+			ipres, err2 := admin.Stats(ctx, &cachegrpc.StatsParams{})
+			if err2 != nil {
+				fmt.Println(describeError(err2))
+				continue
+			}
+			printStats(ipres)
+
+		case iCmd == "flush":
+			// The flush command removes all items, the items of an owner or the items
+			// of a service of an owner, depending on its parameter
+			which, ip, err := parseFlush(iParam)
+			if err != nil {
+				fmt.Println("Error in expression: ", err)
+				continue
+			}
+			var ipres *cachegrpc.FlushResult
+			var err2 error
+			switch which {
+			case "all":
+				ipres, err2 = admin.FlushAll(ctx, ip)
+			case "owner":
+				ipres, err2 = admin.FlushOwner(ctx, ip)
+			default:
+				ipres, err2 = admin.FlushService(ctx, ip)
+			}
+			if err2 != nil {
+				fmt.Println(describeError(err2))
+				continue
+			}
+			fmt.Printf("Removed %d items\n", ipres.Removed)
+
+		case iCmd == "keys":
+			// The keys command lists the IDs of the items, fetching them from the
+			// server one page at a time
+			ip := cachegrpc.ListKeysParams{Prefix: strings.TrimSpace(iParam)}
+			count := 0
+			for {
+				ipres, err2 := admin.ListKeys(ctx, &ip)
+				if err2 != nil {
+					fmt.Println(describeError(err2))
+					break
+				}
+				for _, key := range ipres.Keys {
+					id := item.ID{Owner: key.Owner, Service: key.Service, Name: key.Name}
+					fmt.Println(id.Compose())
+				}
+				count += len(ipres.Keys)
+				if ipres.NextPageToken == "" {
+					fmt.Printf("%d items\n", count)
+					break
+				}
+				ip.PageToken = ipres.NextPageToken
+			}
 
 		case iCmd == "quit":
 			// quit quits the application as an alternative to ctrl+C
@@ -1,21 +1,144 @@
 package main
 
 import (
+	"bytes"
+	"crypto/rand"
 	"flag"
 	"fmt"
+	"io"
 	"log"
 	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
 
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
 
 	"github.com/kamenlilovgocourse/gocourse/project/cachegrpc"
+	"github.com/kamenlilovgocourse/gocourse/project/certs"
+	"github.com/kamenlilovgocourse/gocourse/project/item"
+	"github.com/kamenlilovgocourse/gocourse/project/metrics"
 	"github.com/kamenlilovgocourse/gocourse/project/server"
 )
 
 var (
-	port = flag.Int("port", 3030, "The server port")
+	port             = flag.Int("port", 3030, "The server port")
+	dataDir          = flag.String("data-dir", "", "Directory for the persistence log and snapshots; persistence is disabled if empty")
+	fsync            = flag.Bool("fsync", false, "Sync the persistence log to disk after every change")
+	snapshotInterval = flag.Duration("snapshot-interval", 5*time.Minute, "How often to write a compacted snapshot of the cache, 0 to only write one on startup and shutdown")
+	shards           = flag.Int("shards", item.IDMapsCount, "Number of shards the items are spread over, each with its own lock")
+	maxItems         = flag.Int64("max-items", 0, "Maximum number of items in the cache, 0 for no limit")
+	maxMemory        = flag.Int64("max-memory", 0, "Maximum estimated memory used by the items in bytes, 0 for no limit")
+	evictionPolicy   = flag.String("eviction-policy", "lru", "Which items to evict when over a limit: lru, lfu or random")
+	volatileOnly     = flag.Bool("evict-volatile-only", false, "Only evict items that have an expiry")
+	subscriberQueue  = flag.Int("subscriber-queue", server.DefaultSubscriberQueue, "How many updates a subscriber may fall behind by")
+	slowSubscribers  = flag.String("slow-subscribers", "coalesce", "What to do with subscribers that fall further behind: coalesce (skip to the latest updates) or disconnect")
+	subscriberHist   = flag.Int("subscriber-history", server.DefaultSubscriberHistory, "How many changes of a subscribed item are kept for resuming subscriptions")
+	maxValueSize     = flag.Int("max-value-size", server.DefaultMaxValueSize, "Largest value in bytes that clients may store in an item")
+	auth             = flag.Bool("auth", false, "Require clients to authenticate, and only let them access their own and shared items")
+	authSecretFile   = flag.String("auth-secret-file", "", "File with the secret used to sign client tokens; a random secret is used if empty")
+	adminClients     = flag.String("admin-clients", "", "Comma separated client IDs that may use the Admin service when clients authenticate")
+	tlsCert          = flag.String("tls-cert", "", "Server certificate file in PEM format; TLS is disabled if empty")
+	tlsKey           = flag.String("tls-key", "", "Private key file of the server certificate in PEM format")
+	tlsCA            = flag.String("tls-ca", "", "CA certificates in PEM format used to verify client certificates; client certificates are not requested if empty")
+	metricsAddr      = flag.String("metrics-addr", "", "Address such as :9100 to serve Prometheus metrics on at /metrics; metrics are disabled if empty")
+	shutdownTimeout  = flag.Duration("shutdown-timeout", 10*time.Second, "How long to wait for calls in progress to finish when shutting down")
 )
 
+// Load the secret for signing client tokens, or make up a random one, in which
+// case tokens are only valid until the server restarts
+func loadAuthSecret() ([]byte, error) {
+	if *authSecretFile == "" {
+		secret := make([]byte, 32)
+		_, err := rand.Read(secret)
+		return secret, err
+	}
+	secret, err := os.ReadFile(*authSecretFile)
+	if err != nil {
+		return nil, err
+	}
+	secret = bytes.TrimSpace(secret)
+	if len(secret) == 0 {
+		return nil, fmt.Errorf("%s is empty", *authSecretFile)
+	}
+	return secret, nil
+}
+
+// Wait for done to be closed until the deadline, and report whether it was
+func waitUntil(done <-chan struct{}, deadline time.Time) bool {
+	timer := time.NewTimer(time.Until(deadline))
+	defer timer.Stop()
+	select {
+	case <-done:
+		return true
+	case <-timer.C:
+		return false
+	}
+}
+
+// What shutdown needs of the cache server and of the gRPC server
+type (
+	shutdowner interface {
+		Shutdown()
+	}
+	grpcStopper interface {
+		GracefulStop()
+		Stop()
+	}
+)
+
+// Shut the server down: end the subscription streams, stop accepting
+// connections and let the calls in progress finish, giving up on them when the
+// timeout expires. Then close the rest in order, such as the cache, which stops
+// expiring values and writes out everything kept on disk. That is given the
+// timeout again, so that it is not cut short by calls that had to be ended.
+// Reports whether the closing was done in time
+func shutdown(cacheServer shutdowner, grpcServer grpcStopper, timeout time.Duration, closers ...io.Closer) bool {
+	cacheServer.Shutdown()
+	graceful := make(chan struct{})
+	go func() {
+		grpcServer.GracefulStop()
+		close(graceful)
+	}()
+	if !waitUntil(graceful, time.Now().Add(timeout)) {
+		log.Printf("Calls still in progress after %v, closing their connections\n", timeout)
+		grpcServer.Stop()
+	}
+	deadline := time.Now().Add(timeout)
+	closed := make(chan struct{})
+	go func() {
+		for _, c := range closers {
+			if err := c.Close(); err != nil {
+				log.Printf("Failed to close %T: %v\n", c, err)
+			}
+		}
+		close(closed)
+	}()
+	if !waitUntil(closed, deadline) {
+		log.Printf("Shutdown did not finish in time\n")
+		return false
+	}
+	return true
+}
+
+// Shut the server down once SIGINT or SIGTERM is received, see shutdown. The
+// returned channel receives whether the shutdown finished in time
+func stopOnSignal(grpcServer *grpc.Server, cacheServer *server.CacheServer, closers ...io.Closer) <-chan bool {
+	sigs := make(chan os.Signal, 1)
+	signal.Notify(sigs, syscall.SIGINT, syscall.SIGTERM)
+	done := make(chan bool, 1)
+	go func() {
+		sig := <-sigs
+		log.Printf("Received %v, shutting down\n", sig)
+		done <- shutdown(cacheServer, grpcServer, *shutdownTimeout, closers...)
+	}()
+	return done
+}
+
 // Main routine for the cache item server
 func main() {
 	flag.Parse()
@@ -27,18 +150,108 @@ func main() {
 		log.Fatalf("failed to listen: %v", err)
 	}
 
-	// Spawn a goroutine to handle expiring values. We will notify it that
-	// it's time to shut down by sending a struct{} on the NotifyServerThreadShutdown
-	// channel
-	server.InsertThreadShutdown.Add(1)
-	go server.ScanExpListRoutine()
+	// The cache starts expiring values right away, until it is closed
+	if *shards < 1 {
+		log.Fatalf("-shards must be at least 1")
+	}
+	cache := server.NewSharded(*shards)
+	policy, err := server.EvictionPolicyByName(*evictionPolicy)
+	if err != nil {
+		log.Fatalf("invalid -eviction-policy: %v", err)
+	}
+	cache.SetEvictionLimits(*maxItems, *maxMemory, policy, *volatileOnly)
+	cache.SetMaxValueSize(*maxValueSize)
+	slowPolicy, err := server.SlowSubscriberPolicyByName(*slowSubscribers)
+	if err != nil {
+		log.Fatalf("invalid -slow-subscribers: %v", err)
+	}
+	cache.SetSubscriberQueue(*subscriberQueue, slowPolicy)
+	cache.SetSubscriberHistory(*subscriberHist)
 
-	// Run the grpc server on this thread
-	var opts []grpc.ServerOption
-	grpcServer := grpc.NewServer(opts...)
-	cachegrpc.RegisterCacheServerServer(grpcServer, server.NewServer())
-	grpcServer.Serve(lis)
+	// Restore the cache contents saved by a previous run, and keep saving
+	// changes from now on
+	if *dataDir != "" {
+		if err := cache.EnablePersistence(*dataDir, *fsync, *snapshotInterval); err != nil {
+			log.Fatalf("failed to enable persistence: %v", err)
+		}
+	}
 
-	server.NotifyInsertThreadShutdown <- struct{}{}
-	server.InsertThreadShutdown.Wait()
+	cacheServer := server.NewServer(cache)
+	if *auth {
+		secret, err := loadAuthSecret()
+		if err != nil {
+			log.Fatalf("failed to load auth secret: %v", err)
+		}
+		cacheServer.EnableAuth(secret)
+	}
+	var admins []string
+	if *adminClients != "" {
+		admins = strings.Split(*adminClients, ",")
+	}
+	adminServer := server.NewAdminServer(cacheServer, admins)
+
+	// Serve the metrics over HTTP for Prometheus to scrape
+	var metricsServer *http.Server
+	if *metricsAddr != "" {
+		reg := metrics.NewRegistry()
+		cacheServer.RegisterMetrics(reg)
+		mux := http.NewServeMux()
+		mux.Handle("/metrics", reg)
+		metricsServer = &http.Server{Addr: *metricsAddr, Handler: mux}
+		go func() {
+			if err := metricsServer.ListenAndServe(); err != http.ErrServerClosed {
+				log.Fatalf("failed to serve metrics: %v", err)
+			}
+		}()
+	}
+
+	// Calls must be able to carry the largest value, with room for the rest of
+	// the message. The gRPC default of 4MB is kept for smaller values, so that a
+	// MultiSet can still hold several of them
+	recvSize := 4 << 20
+	if *maxValueSize+64<<10 > recvSize {
+		recvSize = *maxValueSize + 64<<10
+	}
+
+	// Run the grpc server on this thread. Calls are counted before they are
+	// authenticated, so that rejected calls show in the metrics
+	opts := []grpc.ServerOption{
+		grpc.ChainUnaryInterceptor(cacheServer.UnaryMetricsInterceptor, cacheServer.UnaryAuthInterceptor),
+		grpc.ChainStreamInterceptor(cacheServer.StreamMetricsInterceptor, cacheServer.StreamAuthInterceptor),
+		grpc.MaxRecvMsgSize(recvSize),
+		grpc.StatsHandler(adminServer.StatsHandler()),
+	}
+	if *tlsCert != "" {
+		// Certificates are read again on SIGHUP, so they can be renewed without
+		// restarting the server
+		reloader, err := certs.NewReloader(*tlsCert, *tlsKey, *tlsCA)
+		if err != nil {
+			log.Fatalf("failed to load certificates: %v", err)
+		}
+		reloader.ReloadOnSignal(syscall.SIGHUP)
+		opts = append(opts, grpc.Creds(credentials.NewTLS(reloader.ServerConfig())))
+		if *tlsCA != "" {
+			// Clients without a certificate would otherwise be unrestricted
+			// when tokens are not required either
+			cacheServer.RequireClientCerts()
+		}
+	} else if *tlsCA != "" {
+		log.Fatalf("-tls-ca needs -tls-cert and -tls-key")
+	}
+	grpcServer := grpc.NewServer(opts...)
+	cachegrpc.RegisterCacheServerServer(grpcServer, cacheServer)
+	cachegrpc.RegisterAdminServer(grpcServer, adminServer)
+	// The metrics are served until the calls have ended, and the cache is
+	// closed last
+	closers := []io.Closer{cache}
+	if metricsServer != nil {
+		closers = []io.Closer{metricsServer, cache}
+	}
+	done := stopOnSignal(grpcServer, cacheServer, closers...)
+	if err := grpcServer.Serve(lis); err != nil {
+		log.Fatalf("failed to serve: %v", err)
+	}
+	if <-done {
+		log.Printf("Shutdown complete\n")
+	}
 }
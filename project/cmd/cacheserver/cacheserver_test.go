@@ -0,0 +1,112 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/kamenlilovgocourse/gocourse/project/item"
+	"github.com/kamenlilovgocourse/gocourse/project/server"
+	"google.golang.org/grpc"
+)
+
+// Records the order in which shutdown stops things. GracefulStop waits for
+// Stop while stuck is set, as it does with calls that do not end
+type stopRecorder struct {
+	lock  sync.Mutex
+	steps []string
+	stuck bool
+	stop  chan struct{}
+}
+
+func (r *stopRecorder) record(step string) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	r.steps = append(r.steps, step)
+}
+
+func (r *stopRecorder) Shutdown() { r.record("Shutdown") }
+
+func (r *stopRecorder) GracefulStop() {
+	r.record("GracefulStop")
+	if r.stuck {
+		<-r.stop
+	}
+}
+
+func (r *stopRecorder) Stop() {
+	r.record("Stop")
+	close(r.stop)
+}
+
+func (r *stopRecorder) Close() error {
+	r.record("Close")
+	return nil
+}
+
+// Test that shutdown ends the streams before stopping the gRPC server, only
+// forces calls to end when they do not finish in time, and closes the rest last
+func TestShutdownOrder(t *testing.T) {
+	r := &stopRecorder{stop: make(chan struct{})}
+	if !shutdown(r, r, time.Second, r) {
+		t.Fatalf("shutdown() reported a timeout")
+	}
+	if expected := []string{"Shutdown", "GracefulStop", "Close"}; !reflect.DeepEqual(r.steps, expected) {
+		t.Fatalf("shutdown() did %v, expected %v", r.steps, expected)
+	}
+
+	r = &stopRecorder{stuck: true, stop: make(chan struct{})}
+	if !shutdown(r, r, 100*time.Millisecond, r) {
+		t.Fatalf("shutdown() with calls in progress reported a timeout")
+	}
+	if expected := []string{"Shutdown", "GracefulStop", "Stop", "Close"}; !reflect.DeepEqual(r.steps, expected) {
+		t.Fatalf("shutdown() with calls in progress did %v, expected %v", r.steps, expected)
+	}
+}
+
+// A closer that does not return in time
+type slowCloser struct{}
+
+func (slowCloser) Close() error {
+	time.Sleep(time.Second)
+	return nil
+}
+
+// Test that shutdown reports when closing takes longer than the timeout
+func TestShutdownTimeout(t *testing.T) {
+	r := &stopRecorder{stop: make(chan struct{})}
+	if shutdown(r, r, 50*time.Millisecond, slowCloser{}) {
+		t.Fatalf("shutdown() with a slow closer reported success")
+	}
+}
+
+// Test that shutting down closes the cache, which writes the final snapshot,
+// so the items are restored from it on the next start
+func TestShutdownSnapshot(t *testing.T) {
+	dir := t.TempDir()
+	id := item.ID{Owner: "o", Service: "s", Name: "n"}
+	cache := server.New()
+	if err := cache.EnablePersistence(dir, false, time.Hour); err != nil {
+		t.Fatalf("EnablePersistence() returned error %v", err)
+	}
+	cache.Set(id, "v", server.SetOptions{})
+	if !shutdown(server.NewServer(cache), grpc.NewServer(), time.Second, cache) {
+		t.Fatalf("shutdown() reported a timeout")
+	}
+	// The snapshot holds every change, so the log was started over
+	if fi, err := os.Stat(filepath.Join(dir, "cache.log")); err != nil || fi.Size() != 0 {
+		t.Fatalf("log after shutdown() is %v, %v, expected an empty file", fi, err)
+	}
+
+	cache = server.New()
+	defer cache.Close()
+	if err := cache.EnablePersistence(dir, false, time.Hour); err != nil {
+		t.Fatalf("EnablePersistence() after restart returned error %v", err)
+	}
+	if it, err := cache.Get(id); err != nil || it.Value != "v" {
+		t.Fatalf("Get() after restart returned %v, %v, expected value v", it, err)
+	}
+}
@@ -14,14 +14,19 @@ type ID struct {
 	Name    string
 }
 
+// IDMapsCount is the default number of shards items are spread over
 const (
 	IDMapsCount = 128
 )
 
+// An Assignment is a value to set an item to, with an optional expiry. TTL is
+// the expiry as given, relative to when the assignment was parsed, and Expiry
+// the absolute time computed from it; both are unset if there is no expiry
 type Assignment struct {
 	Id     ID
 	Value  string
 	Expiry *time.Time
+	TTL    time.Duration
 }
 
 var parseID *regexp.Regexp
@@ -32,20 +37,73 @@ func init() {
 	parseAssn = regexp.MustCompile(`(?P<ow>[^:]*)\:(?P<se>[^:]*)\:(?P<na>[^:=]*)\=(?P<va>[^,]*)(\,(?P<ex>[0-9]+))?`)
 }
 
-func (id *ID) HashKey() int {
-	hash := 0
-	for _, char := range id.Owner {
-		hash = (hash ^ int(char)) % IDMapsCount
-	}
-	for _, char := range id.Service {
-		hash = (hash ^ int(char)) % IDMapsCount
+// FNV-1a parameters for 64-bit hashes
+const (
+	fnvOffset64 = 14695981039346656037
+	fnvPrime64  = 1099511628211
+)
+
+// Hash returns the 64-bit FNV-1a hash of the ID. The owner and the service are
+// each followed by a zero byte, so that IDs which only differ in where one part
+// ends and the next begins, such as a:bc:d and ab:c:d, hash differently
+func (id *ID) Hash() uint64 {
+	hash := uint64(fnvOffset64)
+	for _, part := range [...]string{id.Owner, id.Service} {
+		for i := 0; i < len(part); i++ {
+			hash ^= uint64(part[i])
+			hash *= fnvPrime64
+		}
+		hash *= fnvPrime64
 	}
-	for _, char := range id.Name {
-		hash = (hash ^ int(char)) % IDMapsCount
+	for i := 0; i < len(id.Name); i++ {
+		hash ^= uint64(id.Name[i])
+		hash *= fnvPrime64
 	}
 	return hash
 }
 
+// HashKey returns the shard of the ID when items are spread over IDMapsCount
+// shards
+func (id *ID) HashKey() int {
+	return int(id.Hash() % IDMapsCount)
+}
+
+// Matches reports whether the ID matches a pattern. The owner, service and
+// name of the pattern may contain the wildcards * (any sequence of characters)
+// and ? (any single character), so owner:service:* matches every item of a
+// service and owner:service:prefix* every item whose name starts with prefix
+func (id *ID) Matches(pattern *ID) bool {
+	return globMatch(pattern.Owner, id.Owner) && globMatch(pattern.Service, id.Service) && globMatch(pattern.Name, id.Name)
+}
+
+// Match s against a pattern with * and ? wildcards. On a mismatch after a *,
+// the * is retried with one more character of s
+func globMatch(pattern, s string) bool {
+	p := []rune(pattern)
+	r := []rune(s)
+	pi, ri := 0, 0
+	starP, starR := -1, 0
+	for ri < len(r) {
+		switch {
+		case pi < len(p) && p[pi] == '*':
+			starP, starR = pi, ri
+			pi++
+		case pi < len(p) && (p[pi] == '?' || p[pi] == r[ri]):
+			pi++
+			ri++
+		case starP >= 0:
+			starR++
+			pi, ri = starP+1, starR
+		default:
+			return false
+		}
+	}
+	for pi < len(p) && p[pi] == '*' {
+		pi++
+	}
+	return pi == len(p)
+}
+
 func (id *ID) Compose() string {
 	return fmt.Sprintf("%s:%s:%s", id.Owner, id.Service, id.Name)
 }
@@ -89,7 +147,8 @@ func ParseAssignment(s string) (Assignment, error) {
 					timeNow := time.Now()
 					as.Expiry = &timeNow
 					expSeconds, _ := strconv.Atoi(match[i])
-					*as.Expiry = as.Expiry.Add(time.Second * time.Duration(expSeconds))
+					as.TTL = time.Second * time.Duration(expSeconds)
+					*as.Expiry = as.Expiry.Add(as.TTL)
 				} else {
 					as.Expiry = nil
 				}
@@ -1,7 +1,9 @@
 package item
 
 import (
+	"fmt"
 	"testing"
+	"time"
 )
 
 // Test ID.Parse with various combinations of input data
@@ -53,5 +55,60 @@ func TestParseAssignment(t *testing.T) {
 	if assn.Expiry == nil {
 		t.Fatalf("ParseAssignment() returned missing Expiry field")
 	}
+	if assn.TTL != 10*time.Second {
+		t.Fatalf("ParseAssignment() returned TTL %v, expected 10s", assn.TTL)
+	}
+
+}
+
+// Test ID.Matches with exact, prefix and wildcard patterns
+func TestIDMatches(t *testing.T) {
+	id := ID{Owner: "ow", Service: "svc", Name: "name1"}
+	matching := []string{"ow:svc:name1", "ow:svc:*", "ow:svc:name*", "ow:*:*", "*:*:*", "ow:svc:n?me?", "o*:s*c:*1", "ow:svc:*name1"}
+	for _, ps := range matching {
+		pattern := ID{}
+		pattern.Parse(ps)
+		if !id.Matches(&pattern) {
+			t.Fatalf("id.Matches(%s) returned false, expected true", ps)
+		}
+	}
+	nonMatching := []string{"ow:svc:name", "ow:svc:name1?", "ow:other:*", "other:*:*", "ow:svc:*2", "ow:svc:?", "ow:sv:*"}
+	for _, ps := range nonMatching {
+		pattern := ID{}
+		pattern.Parse(ps)
+		if id.Matches(&pattern) {
+			t.Fatalf("id.Matches(%s) returned true, expected false", ps)
+		}
+	}
+}
 
+// Test that IDs which only differ in the order of their characters, or in where
+// their parts are split, hash to different values
+func TestHashCollisions(t *testing.T) {
+	pairs := [][2]ID{
+		{{Owner: "o", Service: "s", Name: "ab"}, {Owner: "o", Service: "s", Name: "ba"}},
+		{{Owner: "a", Service: "bc", Name: "d"}, {Owner: "ab", Service: "c", Name: "d"}},
+		{{Owner: "", Service: "ab", Name: "c"}, {Owner: "a", Service: "b", Name: "c"}},
+	}
+	for _, p := range pairs {
+		if p[0].Hash() == p[1].Hash() {
+			t.Fatalf("%s and %s have the same hash", p[0].Compose(), p[1].Compose())
+		}
+	}
+}
+
+// Test that similar names, as generated by programs, are spread evenly over the
+// shards
+func TestHashDistribution(t *testing.T) {
+	const perShard = 1000
+	counts := make([]int, IDMapsCount)
+	for i := 0; i < IDMapsCount*perShard; i++ {
+		id := ID{Owner: "1", Service: "session", Name: fmt.Sprintf("user%d", i)}
+		counts[id.HashKey()]++
+	}
+	for shard, n := range counts {
+		if n < perShard*85/100 || n > perShard*115/100 {
+			t.Fatalf("shard %d holds %d of %d IDs, expected about %d", shard, n, IDMapsCount*perShard, perShard)
+		}
+	}
 }
@@ -0,0 +1,367 @@
+// Package metrics keeps counters, gauges and histograms, and writes them in the
+// Prometheus text exposition format, so that a Prometheus server can scrape them
+// over HTTP. Only what the cache server needs is supported
+package metrics
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+// DefaultBuckets are the upper bounds, in seconds, of the histogram buckets used
+// for the latency of cache calls, which mostly take well below a millisecond
+var DefaultBuckets = []float64{0.0001, 0.00025, 0.0005, 0.001, 0.0025, 0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5}
+
+// A metric family, all samples of one metric name
+type family interface {
+	write(w *bufio.Writer)
+}
+
+// A Registry holds metrics and writes all of them on request. It implements
+// http.Handler, to be served on the path Prometheus scrapes
+type Registry struct {
+	lock     sync.Mutex
+	names    map[string]bool
+	families []family
+}
+
+// NewRegistry returns an empty registry
+func NewRegistry() *Registry {
+	return &Registry{names: make(map[string]bool)}
+}
+
+// Register a family that writes the metrics with the given names
+func (r *Registry) register(f family, names ...string) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	for _, name := range names {
+		if r.names[name] {
+			panic("metrics: " + name + " registered twice")
+		}
+		r.names[name] = true
+	}
+	r.families = append(r.families, f)
+}
+
+// WriteTo writes all metrics to w in the Prometheus text format
+func (r *Registry) WriteTo(w io.Writer) (int64, error) {
+	r.lock.Lock()
+	families := append([]family(nil), r.families...)
+	r.lock.Unlock()
+	cw := &countingWriter{w: w}
+	bw := bufio.NewWriter(cw)
+	for _, f := range families {
+		f.write(bw)
+	}
+	err := bw.Flush()
+	return cw.n, err
+}
+
+func (r *Registry) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	r.WriteTo(w)
+}
+
+type countingWriter struct {
+	w io.Writer
+	n int64
+}
+
+func (cw *countingWriter) Write(p []byte) (int, error) {
+	n, err := cw.w.Write(p)
+	cw.n += int64(n)
+	return n, err
+}
+
+func writeHeader(w *bufio.Writer, name, help, typ string) {
+	help = strings.NewReplacer(`\`, `\\`, "\n", `\n`).Replace(help)
+	fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s %s\n", name, help, name, typ)
+}
+
+var labelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+// Write one sample. extraName and extraValue add a label after the others, as
+// the le label of histogram buckets
+func writeSample(w *bufio.Writer, name string, labels, values []string, extraName, extraValue string, v float64) {
+	w.WriteString(name)
+	if len(labels) > 0 || extraName != "" {
+		w.WriteByte('{')
+		for i := range labels {
+			if i > 0 {
+				w.WriteByte(',')
+			}
+			fmt.Fprintf(w, `%s="%s"`, labels[i], labelEscaper.Replace(values[i]))
+		}
+		if extraName != "" {
+			if len(labels) > 0 {
+				w.WriteByte(',')
+			}
+			fmt.Fprintf(w, `%s="%s"`, extraName, extraValue)
+		}
+		w.WriteByte('}')
+	}
+	w.WriteByte(' ')
+	w.WriteString(formatFloat(v))
+	w.WriteByte('\n')
+}
+
+func formatFloat(v float64) string {
+	switch {
+	case math.IsInf(v, 1):
+		return "+Inf"
+	case math.IsInf(v, -1):
+		return "-Inf"
+	}
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
+
+// Key of the child of a vector with the given label values
+func childKey(values []string) string {
+	return strings.Join(values, "\xff")
+}
+
+// A Counter is a value that only goes up
+type Counter struct {
+	values []string
+	n      uint64
+}
+
+// Inc adds one to the counter
+func (c *Counter) Inc() {
+	atomic.AddUint64(&c.n, 1)
+}
+
+// Add adds n to the counter
+func (c *Counter) Add(n uint64) {
+	atomic.AddUint64(&c.n, n)
+}
+
+// A CounterVec is a set of counters with the same name, told apart by the values
+// of their labels
+type CounterVec struct {
+	name     string
+	help     string
+	labels   []string
+	lock     sync.Mutex
+	children map[string]*Counter
+}
+
+// NewCounterVec registers counters with the given labels
+func (r *Registry) NewCounterVec(name, help string, labels ...string) *CounterVec {
+	v := &CounterVec{name: name, help: help, labels: labels, children: make(map[string]*Counter)}
+	r.register(v, name)
+	return v
+}
+
+// NewCounter registers a counter without labels
+func (r *Registry) NewCounter(name, help string) *Counter {
+	return r.NewCounterVec(name, help).With()
+}
+
+// With returns the counter with the given label values, one per label, creating
+// it on first use
+func (v *CounterVec) With(values ...string) *Counter {
+	if len(values) != len(v.labels) {
+		panic(fmt.Sprintf("metrics: %s needs %d label values, got %d", v.name, len(v.labels), len(values)))
+	}
+	key := childKey(values)
+	v.lock.Lock()
+	defer v.lock.Unlock()
+	c, found := v.children[key]
+	if !found {
+		c = &Counter{values: append([]string(nil), values...)}
+		v.children[key] = c
+	}
+	return c
+}
+
+func (v *CounterVec) write(w *bufio.Writer) {
+	writeHeader(w, v.name, v.help, "counter")
+	v.lock.Lock()
+	keys := make([]string, 0, len(v.children))
+	for key := range v.children {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	children := make([]*Counter, len(keys))
+	for i, key := range keys {
+		children[i] = v.children[key]
+	}
+	v.lock.Unlock()
+	for _, c := range children {
+		writeSample(w, v.name, v.labels, c.values, "", "", float64(atomic.LoadUint64(&c.n)))
+	}
+}
+
+// A metric whose samples are collected when the metrics are written, from
+// state kept elsewhere
+type funcFamily struct {
+	name    string
+	help    string
+	typ     string
+	labels  []string
+	collect func(emit func(value float64, labelValues ...string))
+}
+
+// NewGaugeFunc registers a gauge whose samples are collected by calling collect
+// whenever the metrics are written. collect calls emit once per sample, with a
+// value for each of the labels
+func (r *Registry) NewGaugeFunc(name, help string, labels []string, collect func(emit func(value float64, labelValues ...string))) {
+	r.register(&funcFamily{name: name, help: help, typ: "gauge", labels: labels, collect: collect}, name)
+}
+
+// NewCounterFunc registers a counter without labels whose value is returned by
+// value whenever the metrics are written. The value must never go down
+func (r *Registry) NewCounterFunc(name, help string, value func() float64) {
+	r.register(&funcFamily{name: name, help: help, typ: "counter", collect: func(emit func(float64, ...string)) {
+		emit(value())
+	}}, name)
+}
+
+func (f *funcFamily) write(w *bufio.Writer) {
+	writeHeader(w, f.name, f.help, f.typ)
+	f.collect(func(value float64, labelValues ...string) {
+		writeSample(w, f.name, f.labels, labelValues, "", "", value)
+	})
+}
+
+// Desc describes one of the metrics of a collector. Type is gauge or counter
+type Desc struct {
+	Name   string
+	Help   string
+	Type   string
+	Labels []string
+}
+
+// Metrics whose samples are all collected by one call, from state kept
+// elsewhere, when the metrics are written
+type collector struct {
+	descs   []Desc
+	collect func(emit func(metric int, value float64, labelValues ...string))
+}
+
+// NewCollector registers metrics whose samples are all collected by one call of
+// collect whenever the metrics are written, so that they can be taken from one
+// snapshot of the state they describe. collect calls emit once per sample, with
+// the index in descs of its metric and a value for each of the metric's labels
+func (r *Registry) NewCollector(descs []Desc, collect func(emit func(metric int, value float64, labelValues ...string))) {
+	names := make([]string, len(descs))
+	for i := range descs {
+		names[i] = descs[i].Name
+	}
+	r.register(&collector{descs: descs, collect: collect}, names...)
+}
+
+func (c *collector) write(w *bufio.Writer) {
+	type sample struct {
+		value  float64
+		values []string
+	}
+	samples := make([][]sample, len(c.descs))
+	c.collect(func(metric int, value float64, labelValues ...string) {
+		samples[metric] = append(samples[metric], sample{value: value, values: labelValues})
+	})
+	// Samples of a metric must be written together, after its header
+	for i, d := range c.descs {
+		writeHeader(w, d.Name, d.Help, d.Type)
+		for _, s := range samples[i] {
+			writeSample(w, d.Name, d.Labels, s.values, "", "", s.value)
+		}
+	}
+}
+
+// A Histogram counts observed values in buckets, and keeps their sum
+type Histogram struct {
+	values  []string
+	bounds  []float64
+	lock    sync.Mutex
+	buckets []uint64
+	count   uint64
+	sum     float64
+}
+
+// Observe adds a value to the histogram
+func (h *Histogram) Observe(v float64) {
+	i := sort.SearchFloat64s(h.bounds, v)
+	h.lock.Lock()
+	defer h.lock.Unlock()
+	if i < len(h.buckets) {
+		h.buckets[i]++
+	}
+	h.count++
+	h.sum += v
+}
+
+// A HistogramVec is a set of histograms with the same name and buckets, told
+// apart by the values of their labels
+type HistogramVec struct {
+	name     string
+	help     string
+	labels   []string
+	bounds   []float64
+	lock     sync.Mutex
+	children map[string]*Histogram
+}
+
+// NewHistogramVec registers histograms with the given bucket upper bounds, which
+// must be sorted, and labels
+func (r *Registry) NewHistogramVec(name, help string, bounds []float64, labels ...string) *HistogramVec {
+	v := &HistogramVec{name: name, help: help, labels: labels, bounds: bounds, children: make(map[string]*Histogram)}
+	r.register(v, name)
+	return v
+}
+
+// With returns the histogram with the given label values, one per label,
+// creating it on first use
+func (v *HistogramVec) With(values ...string) *Histogram {
+	if len(values) != len(v.labels) {
+		panic(fmt.Sprintf("metrics: %s needs %d label values, got %d", v.name, len(v.labels), len(values)))
+	}
+	key := childKey(values)
+	v.lock.Lock()
+	defer v.lock.Unlock()
+	h, found := v.children[key]
+	if !found {
+		h = &Histogram{values: append([]string(nil), values...), bounds: v.bounds, buckets: make([]uint64, len(v.bounds))}
+		v.children[key] = h
+	}
+	return h
+}
+
+func (v *HistogramVec) write(w *bufio.Writer) {
+	writeHeader(w, v.name, v.help, "histogram")
+	v.lock.Lock()
+	keys := make([]string, 0, len(v.children))
+	for key := range v.children {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	children := make([]*Histogram, len(keys))
+	for i, key := range keys {
+		children[i] = v.children[key]
+	}
+	v.lock.Unlock()
+	for _, h := range children {
+		h.lock.Lock()
+		buckets := append([]uint64(nil), h.buckets...)
+		count, sum := h.count, h.sum
+		h.lock.Unlock()
+		// Buckets are cumulative in the exposition format
+		var cumulative uint64
+		for i, bound := range v.bounds {
+			cumulative += buckets[i]
+			writeSample(w, v.name+"_bucket", v.labels, h.values, "le", formatFloat(bound), float64(cumulative))
+		}
+		writeSample(w, v.name+"_bucket", v.labels, h.values, "le", "+Inf", float64(count))
+		writeSample(w, v.name+"_sum", v.labels, h.values, "", "", sum)
+		writeSample(w, v.name+"_count", v.labels, h.values, "", "", float64(count))
+	}
+}
@@ -0,0 +1,83 @@
+package metrics
+
+import (
+	"strings"
+	"testing"
+)
+
+// Test that counters, gauges and histograms are written in the Prometheus text
+// format, with cumulative buckets and escaped label values
+func TestWriteTo(t *testing.T) {
+	r := NewRegistry()
+	calls := r.NewCounterVec("calls_total", "Calls by method", "method")
+	calls.With("Get").Inc()
+	calls.With("Get").Add(2)
+	calls.With(`Odd"name`).Inc()
+	r.NewGaugeFunc("shard_items", "Items per shard", []string{"shard"}, func(emit func(float64, ...string)) {
+		emit(4, "0")
+		emit(5, "1")
+	})
+	latency := r.NewHistogramVec("latency_seconds", "Latency", []float64{0.1, 1}, "method")
+	latency.With("Get").Observe(0.05)
+	latency.With("Get").Observe(0.5)
+	latency.With("Get").Observe(5)
+
+	var out strings.Builder
+	if _, err := r.WriteTo(&out); err != nil {
+		t.Fatalf("WriteTo() returned error %v", err)
+	}
+	expected := `# HELP calls_total Calls by method
+# TYPE calls_total counter
+calls_total{method="Get"} 3
+calls_total{method="Odd\"name"} 1
+# HELP shard_items Items per shard
+# TYPE shard_items gauge
+shard_items{shard="0"} 4
+shard_items{shard="1"} 5
+# HELP latency_seconds Latency
+# TYPE latency_seconds histogram
+latency_seconds_bucket{method="Get",le="0.1"} 1
+latency_seconds_bucket{method="Get",le="1"} 2
+latency_seconds_bucket{method="Get",le="+Inf"} 3
+latency_seconds_sum{method="Get"} 5.55
+latency_seconds_count{method="Get"} 3
+`
+	if out.String() != expected {
+		t.Fatalf("WriteTo() wrote\n%s\nexpected\n%s", out.String(), expected)
+	}
+}
+
+// Test that the metrics of a collector are collected by one call per write, and
+// that the samples of each metric are written together after its header
+func TestCollector(t *testing.T) {
+	r := NewRegistry()
+	calls := 0
+	r.NewCollector([]Desc{
+		{Name: "shard_items", Help: "Items per shard", Type: "gauge", Labels: []string{"shard"}},
+		{Name: "evicted_total", Help: "Evicted items", Type: "counter"},
+	}, func(emit func(int, float64, ...string)) {
+		calls++
+		emit(0, 4, "0")
+		emit(1, 7)
+		emit(0, 5, "1")
+	})
+
+	var out strings.Builder
+	if _, err := r.WriteTo(&out); err != nil {
+		t.Fatalf("WriteTo() returned error %v", err)
+	}
+	expected := `# HELP shard_items Items per shard
+# TYPE shard_items gauge
+shard_items{shard="0"} 4
+shard_items{shard="1"} 5
+# HELP evicted_total Evicted items
+# TYPE evicted_total counter
+evicted_total 7
+`
+	if out.String() != expected {
+		t.Fatalf("WriteTo() wrote\n%s\nexpected\n%s", out.String(), expected)
+	}
+	if calls != 1 {
+		t.Fatalf("collector was called %d times for one write, expected 1", calls)
+	}
+}
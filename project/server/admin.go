@@ -0,0 +1,307 @@
+package server
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"sort"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/kamenlilovgocourse/gocourse/project/cachegrpc"
+	"github.com/kamenlilovgocourse/gocourse/project/item"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/stats"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+// Number of keys returned by ListKeys when the caller does not ask for a page
+// size, and the most it returns at once
+const (
+	defaultPageSize = 100
+	maxPageSize     = 1000
+)
+
+// FlushAll removes every item from the cache, and returns how many there were
+func (c *Cache) FlushAll() int {
+	return c.flush(func(*item.ID) bool { return true })
+}
+
+// FlushOwner removes every item of an owner, and returns how many there were
+func (c *Cache) FlushOwner(owner string) int {
+	return c.flush(func(id *item.ID) bool { return id.Owner == owner })
+}
+
+// FlushService removes every item of an owner under a service, and returns
+// how many there were
+func (c *Cache) FlushService(owner string, service string) int {
+	return c.flush(func(id *item.ID) bool { return id.Owner == owner && id.Service == service })
+}
+
+// Remove the items whose IDs match, one shard at a time. Every item is removed
+// as Delete would: its pending expiry is cancelled and its subscribers are
+// notified
+func (c *Cache) flush(match func(id *item.ID) bool) int {
+	type removed struct {
+		id item.ID
+		me mapEntry
+	}
+	count := 0
+	for hash := range c.maps {
+		var gone []removed
+		c.mapsLock[hash].Lock()
+		for key, me := range c.maps[hash] {
+			as := item.ID{}
+			as.Parse(key)
+			if !me.Present || !match(&as) {
+				continue
+			}
+			c.removeEntry(hash, &as, &me, EventDeleted)
+			c.logRemove(opDelete, &as)
+			gone = append(gone, removed{id: as, me: me})
+		}
+		c.mapsLock[hash].Unlock()
+		for i := range gone {
+			if gone[i].me.Expiry != nil {
+				c.cancelExpiry(&gone[i].id, gone[i].me.Generation)
+			}
+		}
+		count += len(gone)
+	}
+	return count
+}
+
+// A KeyCursor marks where a listing of keys stopped. The zero value starts from
+// the beginning
+type KeyCursor struct {
+	Shard int
+	// Last key returned from Shard, or "" to start with the first key of it
+	Key string
+}
+
+// ListKeys returns up to limit IDs of items whose owner:service:name starts
+// with prefix, from where the cursor points. Keys are listed shard by shard, in
+// sorted order within a shard. The returned cursor continues the listing, and
+// is nil when there are no more keys. Items set or removed while listing may or
+// may not be returned
+func (c *Cache) ListKeys(prefix string, from KeyCursor, limit int) ([]item.ID, *KeyCursor) {
+	var ids []item.ID
+	for hash := from.Shard; hash < len(c.maps); hash++ {
+		if len(ids) == limit {
+			return ids, &KeyCursor{Shard: hash}
+		}
+		var keys []string
+		c.mapsLock[hash].Lock()
+		for key, me := range c.maps[hash] {
+			if me.Present && strings.HasPrefix(key, prefix) && (hash != from.Shard || key > from.Key) {
+				keys = append(keys, key)
+			}
+		}
+		c.mapsLock[hash].Unlock()
+		sort.Strings(keys)
+		for i, key := range keys {
+			if len(ids) == limit {
+				return ids, &KeyCursor{Shard: hash, Key: keys[i-1]}
+			}
+			as := item.ID{}
+			as.Parse(key)
+			ids = append(ids, as)
+		}
+	}
+	return ids, nil
+}
+
+// The gRPC Admin service, for operators of a CacheServer. It counts the
+// connections to the gRPC server when installed as its stats handler, see
+// StatsHandler
+type AdminServer struct {
+	cachegrpc.UnimplementedAdminServer
+	server  *CacheServer
+	started time.Time
+	// Client IDs that may call the service when the caller is known
+	admins      map[string]bool
+	connections int64
+}
+
+// NewAdminServer returns the Admin service of a cache server. When clients
+// authenticate, only the given client IDs may use it, except that any client
+// may flush its own items
+func NewAdminServer(s *CacheServer, admins []string) *AdminServer {
+	a := &AdminServer{server: s, started: time.Now(), admins: make(map[string]bool)}
+	for _, id := range admins {
+		a.admins[id] = true
+	}
+	return a
+}
+
+// StatsHandler returns a handler to pass to grpc.StatsHandler, so that Stats
+// can report the number of connected clients
+func (a *AdminServer) StatsHandler() stats.Handler {
+	return connCounter{&a.connections}
+}
+
+// Counts the open connections of a gRPC server
+type connCounter struct {
+	count *int64
+}
+
+func (cc connCounter) TagRPC(ctx context.Context, _ *stats.RPCTagInfo) context.Context {
+	return ctx
+}
+
+func (cc connCounter) HandleRPC(context.Context, stats.RPCStats) {}
+
+func (cc connCounter) TagConn(ctx context.Context, _ *stats.ConnTagInfo) context.Context {
+	return ctx
+}
+
+func (cc connCounter) HandleConn(_ context.Context, s stats.ConnStats) {
+	switch s.(type) {
+	case *stats.ConnBegin:
+		atomic.AddInt64(cc.count, 1)
+	case *stats.ConnEnd:
+		atomic.AddInt64(cc.count, -1)
+	}
+}
+
+// Return a PermissionDenied error unless the caller in ctx is an administrator,
+// or unknown. With a non-empty owner, that owner may also make the call
+func (a *AdminServer) checkAdmin(ctx context.Context, owner string) error {
+	caller := callerOf(ctx)
+	if caller == "" || a.admins[caller] || (owner != "" && owner == caller) {
+		return nil
+	}
+	st := status.New(codes.PermissionDenied, "caller is not an administrator")
+	detail, err := st.WithDetails(&errdetails.ErrorInfo{
+		Reason:   "NOT_ADMIN",
+		Domain:   "cacheserver",
+		Metadata: map[string]string{"caller": caller},
+	})
+	if err == nil {
+		st = detail
+	}
+	return st.Err()
+}
+
+// Stats reports the size and activity of the cache and the server
+func (a *AdminServer) Stats(ctx context.Context, p *cachegrpc.StatsParams) (*cachegrpc.StatsResult, error) {
+	if err := a.checkAdmin(ctx, ""); err != nil {
+		return nil, err
+	}
+	st := a.server.cache.Stats()
+	res := &cachegrpc.StatsResult{
+		Uptime:           durationpb.New(time.Since(a.started)),
+		Items:            st.Items,
+		MemoryBytes:      st.Memory,
+		Subscriptions:    st.Subscriptions,
+		ConnectedClients: atomic.LoadInt64(&a.connections),
+		Evictions:        st.Evictions,
+		Expirations:      st.Expirations,
+		PendingExpiries:  int64(st.PendingExpiries),
+		SubscriberLagMax: int64(st.SubscriberLag),
+		DroppedUpdates:   st.DroppedUpdates,
+		SlowDisconnects:  st.SlowDisconnects,
+	}
+	for _, shard := range st.Shards {
+		res.Shards = append(res.Shards, &cachegrpc.ShardStats{Items: shard.Items, MemoryBytes: shard.Memory})
+	}
+	for _, sub := range st.Subscribers {
+		res.Subscribers = append(res.Subscribers, &cachegrpc.SubscriberStats{Owner: sub.ID.Owner, Service: sub.ID.Service, Name: sub.ID.Name,
+			Pattern: sub.Pattern, Lag: int64(sub.Lag), DroppedUpdates: sub.Dropped})
+	}
+	return res, nil
+}
+
+// FlushAll removes every item from the cache
+func (a *AdminServer) FlushAll(ctx context.Context, p *cachegrpc.FlushParams) (*cachegrpc.FlushResult, error) {
+	if err := a.checkAdmin(ctx, ""); err != nil {
+		return nil, err
+	}
+	return &cachegrpc.FlushResult{Removed: int64(a.server.cache.FlushAll())}, nil
+}
+
+// FlushOwner removes every item of an owner
+func (a *AdminServer) FlushOwner(ctx context.Context, p *cachegrpc.FlushParams) (*cachegrpc.FlushResult, error) {
+	if err := a.checkAdmin(ctx, p.Owner); err != nil {
+		return nil, err
+	}
+	return &cachegrpc.FlushResult{Removed: int64(a.server.cache.FlushOwner(p.Owner))}, nil
+}
+
+// FlushService removes every item of an owner under a service
+func (a *AdminServer) FlushService(ctx context.Context, p *cachegrpc.FlushParams) (*cachegrpc.FlushResult, error) {
+	if err := a.checkAdmin(ctx, p.Owner); err != nil {
+		return nil, err
+	}
+	if p.Service == "" {
+		return nil, badRequest("service", "service must not be empty")
+	}
+	return &cachegrpc.FlushResult{Removed: int64(a.server.cache.FlushService(p.Owner, p.Service))}, nil
+}
+
+// ListKeys returns one page of the IDs of the items in the cache
+func (a *AdminServer) ListKeys(ctx context.Context, p *cachegrpc.ListKeysParams) (*cachegrpc.ListKeysResult, error) {
+	if err := a.checkAdmin(ctx, ""); err != nil {
+		return nil, err
+	}
+	limit := int(p.PageSize)
+	switch {
+	case limit < 0:
+		return nil, badRequest("page_size", "page size must not be negative")
+	case limit == 0:
+		limit = defaultPageSize
+	case limit > maxPageSize:
+		limit = maxPageSize
+	}
+	from, err := decodePageToken(p.PageToken)
+	if err != nil {
+		return nil, badRequest("page_token", "invalid page token")
+	}
+	ids, next := a.server.cache.ListKeys(p.Prefix, from, limit)
+	res := &cachegrpc.ListKeysResult{}
+	for _, id := range ids {
+		res.Keys = append(res.Keys, &cachegrpc.GetItemParams{Owner: id.Owner, Service: id.Service, Name: id.Name})
+	}
+	if next != nil {
+		res.NextPageToken = encodePageToken(next)
+	}
+	return res, nil
+}
+
+// Page tokens are the shard and the last key of a KeyCursor, in base64 so that
+// clients treat them as opaque
+func encodePageToken(cur *KeyCursor) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(strconv.Itoa(cur.Shard) + "/" + cur.Key))
+}
+
+func decodePageToken(token string) (KeyCursor, error) {
+	if token == "" {
+		return KeyCursor{}, nil
+	}
+	b, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return KeyCursor{}, err
+	}
+	shard, key, _ := strings.Cut(string(b), "/")
+	n, err := strconv.Atoi(shard)
+	if err != nil || n < 0 {
+		return KeyCursor{}, errors.New("invalid shard")
+	}
+	return KeyCursor{Shard: n, Key: key}, nil
+}
+
+// Return an InvalidArgument error with a BadRequest detail naming the field
+func badRequest(field string, description string) error {
+	st := status.New(codes.InvalidArgument, description)
+	detail, err := st.WithDetails(&errdetails.BadRequest{FieldViolations: []*errdetails.BadRequest_FieldViolation{
+		{Field: field, Description: description},
+	}})
+	if err == nil {
+		st = detail
+	}
+	return st.Err()
+}
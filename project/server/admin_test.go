@@ -0,0 +1,158 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/kamenlilovgocourse/gocourse/project/cachegrpc"
+	"github.com/kamenlilovgocourse/gocourse/project/item"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Test that the flushes remove exactly the matching items, and notify their
+// subscribers
+func TestFlush(t *testing.T) {
+	c := newCache()
+	for _, owner := range []string{"a", "b"} {
+		for _, service := range []string{"s", "t"} {
+			for i := 0; i < 3; i++ {
+				if _, err := c.Set(item.ID{Owner: owner, Service: service, Name: fmt.Sprint(i)}, "v", SetOptions{}); err != nil {
+					t.Fatalf("Set() returned error %v", err)
+				}
+			}
+		}
+	}
+	sub := c.Subscribe(item.ID{Owner: "a", Service: "s", Name: "0"})
+
+	if n := c.FlushService("a", "s"); n != 3 {
+		t.Fatalf("FlushService() removed %d items, expected 3", n)
+	}
+	if u := <-sub.ch; u.Event != EventDeleted {
+		t.Fatalf("subscriber received event %v, expected EventDeleted", u.Event)
+	}
+	if n := c.FlushOwner("a"); n != 3 {
+		t.Fatalf("FlushOwner() removed %d items, expected 3", n)
+	}
+	if _, err := c.Get(item.ID{Owner: "b", Service: "s", Name: "0"}); err != nil {
+		t.Fatalf("an item of another owner was flushed: %v", err)
+	}
+	if n := c.FlushAll(); n != 6 {
+		t.Fatalf("FlushAll() removed %d items, expected 6", n)
+	}
+	if st := c.Stats(); st.Items != 0 || st.Memory != 0 {
+		t.Fatalf("cache holds %d items of %d bytes after FlushAll()", st.Items, st.Memory)
+	}
+}
+
+// Test that paging through ListKeys returns every matching key exactly once,
+// whatever the page size
+func TestListKeys(t *testing.T) {
+	c := newCache()
+	a := NewAdminServer(NewServer(c), nil)
+	for i := 0; i < 50; i++ {
+		c.Set(item.ID{Owner: "o", Service: "keep", Name: fmt.Sprint(i)}, "v", SetOptions{})
+		c.Set(item.ID{Owner: "o", Service: "skip", Name: fmt.Sprint(i)}, "v", SetOptions{})
+	}
+	for _, size := range []int32{1, 7, 50, 0} {
+		seen := make(map[string]bool)
+		p := &cachegrpc.ListKeysParams{Prefix: "o:keep:", PageSize: size}
+		for {
+			res, err := a.ListKeys(context.Background(), p)
+			if err != nil {
+				t.Fatalf("ListKeys() returned error %v", err)
+			}
+			if size > 0 && len(res.Keys) > int(size) {
+				t.Fatalf("ListKeys() returned %d keys, more than the page size %d", len(res.Keys), size)
+			}
+			for _, key := range res.Keys {
+				id := item.ID{Owner: key.Owner, Service: key.Service, Name: key.Name}
+				if key.Service != "keep" || seen[id.Compose()] {
+					t.Fatalf("ListKeys() with page size %d returned %s unexpectedly", size, id.Compose())
+				}
+				seen[id.Compose()] = true
+			}
+			if res.NextPageToken == "" {
+				break
+			}
+			p.PageToken = res.NextPageToken
+		}
+		if len(seen) != 50 {
+			t.Fatalf("ListKeys() with page size %d returned %d keys, expected 50", size, len(seen))
+		}
+	}
+
+	_, err := a.ListKeys(context.Background(), &cachegrpc.ListKeysParams{PageToken: "!"})
+	if status.Code(err) != codes.InvalidArgument {
+		t.Fatalf("ListKeys() with a bad page token returned %v, expected InvalidArgument", err)
+	}
+}
+
+// Test that only administrators may use the Admin service, except for flushing
+// their own items
+func TestAdminAccess(t *testing.T) {
+	s := NewServer(newCache())
+	s.EnableAuth([]byte("secret"))
+	a := NewAdminServer(s, []string{"root"})
+	root := authContext(t, s, s.issueToken("root"))
+	bob := authContext(t, s, s.issueToken("bob"))
+
+	if _, err := a.Stats(root, &cachegrpc.StatsParams{}); err != nil {
+		t.Fatalf("Stats() by an administrator returned error %v", err)
+	}
+	if _, err := a.Stats(bob, &cachegrpc.StatsParams{}); status.Code(err) != codes.PermissionDenied {
+		t.Fatalf("Stats() by another client returned %v, expected PermissionDenied", err)
+	}
+	if _, err := a.FlushAll(bob, &cachegrpc.FlushParams{}); status.Code(err) != codes.PermissionDenied {
+		t.Fatalf("FlushAll() by another client returned %v, expected PermissionDenied", err)
+	}
+	if _, err := a.FlushOwner(bob, &cachegrpc.FlushParams{Owner: "root"}); status.Code(err) != codes.PermissionDenied {
+		t.Fatalf("FlushOwner() of another owner returned %v, expected PermissionDenied", err)
+	}
+	if _, err := a.FlushService(bob, &cachegrpc.FlushParams{Owner: "bob", Service: "s"}); err != nil {
+		t.Fatalf("FlushService() of the caller's own items returned error %v", err)
+	}
+}
+
+// Test that Stats lists the lag and dropped updates of every subscription,
+// with the item or pattern it is subscribed to
+func TestStatsSubscribers(t *testing.T) {
+	c := newCache()
+	c.SetSubscriberQueue(2, CoalesceSlowSubscribers)
+	a := NewAdminServer(NewServer(c), nil)
+	n := item.ID{Owner: "o", Service: "s", Name: "n"}
+	for _, sub := range []*Subscription{
+		c.Subscribe(n),
+		c.Subscribe(item.ID{Owner: "o", Service: "s", Name: "m"}),
+		c.SubscribePattern(item.ID{Owner: "o", Service: "s", Name: "*"}, nil),
+	} {
+		defer sub.Close()
+	}
+	for i := 0; i < 3; i++ {
+		c.Set(n, fmt.Sprint(i), SetOptions{})
+	}
+
+	st, err := a.Stats(context.Background(), &cachegrpc.StatsParams{})
+	if err != nil {
+		t.Fatalf("Stats() returned error %v", err)
+	}
+	expected := []*cachegrpc.SubscriberStats{
+		{Owner: "o", Service: "s", Name: "*", Pattern: true, Lag: 2, DroppedUpdates: 1},
+		{Owner: "o", Service: "s", Name: "m"},
+		{Owner: "o", Service: "s", Name: "n", Lag: 2, DroppedUpdates: 1},
+	}
+	if len(st.Subscribers) != len(expected) {
+		t.Fatalf("Stats() returned %d subscribers, expected %d", len(st.Subscribers), len(expected))
+	}
+	for i, exp := range expected {
+		got := st.Subscribers[i]
+		if got.Owner != exp.Owner || got.Service != exp.Service || got.Name != exp.Name || got.Pattern != exp.Pattern ||
+			got.Lag != exp.Lag || got.DroppedUpdates != exp.DroppedUpdates {
+			t.Fatalf("Stats() returned subscriber %v, expected %v", got, exp)
+		}
+	}
+	if st.SubscriberLagMax != 2 || st.DroppedUpdates != 2 {
+		t.Fatalf("Stats() returned lag %d and %d dropped updates, expected 2 and 2", st.SubscriberLagMax, st.DroppedUpdates)
+	}
+}
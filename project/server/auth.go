@@ -0,0 +1,233 @@
+package server
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"strings"
+
+	"github.com/kamenlilovgocourse/gocourse/project/cachegrpc"
+	"github.com/kamenlilovgocourse/gocourse/project/item"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+// Metadata key under which clients send the token returned by GetClientID
+const TokenMetadataKey = "token"
+
+// Grantee that stands for every client in an ACL
+const anyClient = "*"
+
+// Methods that may be called without a token
+var publicMethods = map[string]bool{
+	"/cachegrpc.CacheServer/GetClientID": true,
+}
+
+// Access given by an owner to the items of one of its services
+type grant struct {
+	read  bool
+	write bool
+}
+
+// Context key of the authenticated client ID
+type callerKey struct{}
+
+// EnableAuth makes the server require a token signed with secret on every call
+// but GetClientID, and restricts callers to their own items and the items shared
+// with them via Grant. Client IDs handed out from then on are random, so that
+// they stay unique across restarts that keep the same secret
+func (s *CacheServer) EnableAuth(secret []byte) {
+	s.authSecret = secret
+}
+
+// RequireClientCerts makes the server reject calls from clients that present no
+// verified TLS certificate, unless authentication is enabled and they identify
+// themselves with a token instead. It is meant for servers that verify client
+// certificates, so that a client can not access every item just by leaving its
+// certificate out
+func (s *CacheServer) RequireClientCerts() {
+	s.requireCerts = true
+}
+
+// Sign a client ID. The token is the ID followed by its HMAC, so it can be
+// verified without keeping any per-client state on the server
+func (s *CacheServer) issueToken(id string) string {
+	mac := hmac.New(sha256.New, s.authSecret)
+	mac.Write([]byte(id))
+	return id + "." + base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// Check a token and return the client ID it was issued for
+func (s *CacheServer) verifyToken(token string) (string, error) {
+	dot := strings.LastIndex(token, ".")
+	if dot < 0 {
+		return "", status.Error(codes.Unauthenticated, "malformed token")
+	}
+	id := token[:dot]
+	if !hmac.Equal([]byte(s.issueToken(id)), []byte(token)) {
+		return "", status.Error(codes.Unauthenticated, "invalid token")
+	}
+	return id, nil
+}
+
+// Return a new random client ID, used instead of the counter when
+// authentication is enabled
+func randomClientID() string {
+	b := make([]byte, 8)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+// Return the common name of the verified TLS client certificate of the peer of
+// an incoming call, or "" if it did not present one
+func certIdentity(ctx context.Context) string {
+	p, ok := peer.FromContext(ctx)
+	if !ok {
+		return ""
+	}
+	info, ok := p.AuthInfo.(credentials.TLSInfo)
+	if !ok || len(info.State.VerifiedChains) == 0 || len(info.State.VerifiedChains[0]) == 0 {
+		return ""
+	}
+	return info.State.VerifiedChains[0][0].Subject.CommonName
+}
+
+// Identify the caller of an incoming call, and return a context carrying its
+// client ID. A client with a verified TLS certificate is identified by the
+// certificate's common name and needs no token; otherwise, when authentication
+// is enabled, the token in the metadata of the call is verified. A caller that
+// can not be identified is rejected if the server requires client certificates
+func (s *CacheServer) authenticate(ctx context.Context, method string) (context.Context, error) {
+	if id := certIdentity(ctx); id != "" {
+		return context.WithValue(ctx, callerKey{}, id), nil
+	}
+	if s.authSecret == nil {
+		if s.requireCerts {
+			return nil, status.Error(codes.Unauthenticated, "missing client certificate")
+		}
+		return ctx, nil
+	}
+	if publicMethods[method] {
+		return ctx, nil
+	}
+	md, _ := metadata.FromIncomingContext(ctx)
+	tokens := md.Get(TokenMetadataKey)
+	if len(tokens) == 0 {
+		return nil, status.Error(codes.Unauthenticated, "missing token, call GetClientID first")
+	}
+	id, err := s.verifyToken(tokens[0])
+	if err != nil {
+		return nil, err
+	}
+	return context.WithValue(ctx, callerKey{}, id), nil
+}
+
+// UnaryAuthInterceptor identifies the caller of unary calls, see authenticate
+func (s *CacheServer) UnaryAuthInterceptor(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	ctx, err := s.authenticate(ctx, info.FullMethod)
+	if err != nil {
+		return nil, err
+	}
+	return handler(ctx, req)
+}
+
+// A server stream whose context carries the caller's client ID
+type authStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (as *authStream) Context() context.Context {
+	return as.ctx
+}
+
+// StreamAuthInterceptor identifies the caller of streaming calls, see authenticate
+func (s *CacheServer) StreamAuthInterceptor(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	ctx, err := s.authenticate(ss.Context(), info.FullMethod)
+	if err != nil {
+		return err
+	}
+	return handler(srv, &authStream{ServerStream: ss, ctx: ctx})
+}
+
+// Return the client ID of the caller, or "" if it is not known, because
+// authentication is disabled and the caller has no client certificate
+func callerOf(ctx context.Context) string {
+	id, _ := ctx.Value(callerKey{}).(string)
+	return id
+}
+
+// Report whether the client may read, or also write, the item with the given ID.
+// Callers that are not known may access everything
+func (s *CacheServer) mayAccess(caller string, id *item.ID, write bool) bool {
+	if caller == "" || id.Owner == caller {
+		return true
+	}
+	s.aclLock.Lock()
+	defer s.aclLock.Unlock()
+	grants := s.acls[id.Owner+":"+id.Service]
+	for _, grantee := range []string{caller, anyClient} {
+		g, found := grants[grantee]
+		if found && (g.write || (!write && g.read)) {
+			return true
+		}
+	}
+	return false
+}
+
+// Return a PermissionDenied error unless the caller in ctx may access the item.
+// The error details name the item and the access that is missing
+func (s *CacheServer) checkAccess(ctx context.Context, id *item.ID, write bool) error {
+	caller := callerOf(ctx)
+	if s.mayAccess(caller, id, write) {
+		return nil
+	}
+	access := "read"
+	if write {
+		access = "write"
+	}
+	st := status.Newf(codes.PermissionDenied, "no %s access to %s", access, id.Compose())
+	detail, err := st.WithDetails(&errdetails.ErrorInfo{
+		Reason:   "NO_" + strings.ToUpper(access) + "_ACCESS",
+		Domain:   "cacheserver",
+		Metadata: map[string]string{"item": id.Compose(), "caller": caller},
+	})
+	if err == nil {
+		st = detail
+	}
+	return st.Err()
+}
+
+// Grant shares the caller's items under one service with another client, or with
+// every client. Write access implies read access. Grants are kept in memory only
+func (s *CacheServer) Grant(ctx context.Context, p *cachegrpc.GrantParams) (*cachegrpc.GrantResult, error) {
+	if callerOf(ctx) == "" {
+		return nil, status.Error(codes.FailedPrecondition, "grants need an authenticated client")
+	}
+	if p.Service == "" || p.Grantee == "" {
+		return nil, status.Error(codes.InvalidArgument, "service and grantee must not be empty")
+	}
+	key := callerOf(ctx) + ":" + p.Service
+	s.aclLock.Lock()
+	defer s.aclLock.Unlock()
+	if !p.Read && !p.Write {
+		delete(s.acls[key], p.Grantee)
+		if len(s.acls[key]) == 0 {
+			delete(s.acls, key)
+		}
+		return &cachegrpc.GrantResult{}, nil
+	}
+	if s.acls[key] == nil {
+		s.acls[key] = make(map[string]grant)
+	}
+	s.acls[key][p.Grantee] = grant{read: p.Read, write: p.Write}
+	return &cachegrpc.GrantResult{}, nil
+}
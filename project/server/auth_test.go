@@ -0,0 +1,85 @@
+package server
+
+import (
+	"context"
+	"testing"
+
+	"github.com/kamenlilovgocourse/gocourse/project/cachegrpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// Authenticate a call carrying the given token, as the interceptors do
+func authContext(t *testing.T, s *CacheServer, token string) context.Context {
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs(TokenMetadataKey, token))
+	ctx, err := s.authenticate(ctx, "/cachegrpc.CacheServer/GetItem")
+	if err != nil {
+		t.Fatalf("authenticate() rejected a valid token: %v", err)
+	}
+	return ctx
+}
+
+// Test that tokens are only accepted as issued, and that calls without one are
+// rejected except for GetClientID
+func TestAuthenticate(t *testing.T) {
+	s := NewServer(newCache())
+	s.EnableAuth([]byte("secret"))
+	id, err := s.GetClientID(context.Background(), &cachegrpc.AssignClientID{})
+	if err != nil || id.Token == "" {
+		t.Fatalf("GetClientID() did not return a token")
+	}
+	if got := callerOf(authContext(t, s, id.Token)); got != id.Id {
+		t.Fatalf("authenticate() returned caller %q, expected %q", got, id.Id)
+	}
+
+	for _, token := range []string{"", "nodot", id.Token + "x", "other" + id.Token[len(id.Id):]} {
+		ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs(TokenMetadataKey, token))
+		if _, err := s.authenticate(ctx, "/cachegrpc.CacheServer/GetItem"); status.Code(err) != codes.Unauthenticated {
+			t.Fatalf("authenticate() of token %q returned %v, expected Unauthenticated", token, err)
+		}
+	}
+	if _, err := s.authenticate(context.Background(), "/cachegrpc.CacheServer/SetItem"); status.Code(err) != codes.Unauthenticated {
+		t.Fatalf("authenticate() without a token returned %v, expected Unauthenticated", err)
+	}
+	if _, err := s.authenticate(context.Background(), "/cachegrpc.CacheServer/GetClientID"); err != nil {
+		t.Fatalf("authenticate() of GetClientID without a token returned %v", err)
+	}
+}
+
+// Test that clients may only access the items of other owners that were shared
+// with them, and only in the granted way
+func TestAccessGrants(t *testing.T) {
+	s := NewServer(newCache())
+	s.EnableAuth([]byte("secret"))
+	alice := authContext(t, s, s.issueToken("alice"))
+	bob := authContext(t, s, s.issueToken("bob"))
+	set := &cachegrpc.SetItemParams{Owner: "alice", Service: "s", Name: "n", Value: "v"}
+	get := &cachegrpc.GetItemParams{Owner: "alice", Service: "s", Name: "n"}
+
+	if _, err := s.SetItem(alice, set); err != nil {
+		t.Fatalf("SetItem() of own item returned %v", err)
+	}
+	if _, err := s.GetItem(bob, get); status.Code(err) != codes.PermissionDenied {
+		t.Fatalf("GetItem() of another owner's item returned %v, expected PermissionDenied", err)
+	}
+
+	s.Grant(alice, &cachegrpc.GrantParams{Service: "s", Grantee: "bob", Read: true})
+	if _, err := s.GetItem(bob, get); err != nil {
+		t.Fatalf("GetItem() with read access returned %v", err)
+	}
+	if _, err := s.SetItem(bob, set); status.Code(err) != codes.PermissionDenied {
+		t.Fatalf("SetItem() with read access returned %v, expected PermissionDenied", err)
+	}
+
+	s.Grant(alice, &cachegrpc.GrantParams{Service: "s", Grantee: "*", Read: true, Write: true})
+	if _, err := s.SetItem(bob, set); err != nil {
+		t.Fatalf("SetItem() with write access for everyone returned %v", err)
+	}
+
+	s.Grant(alice, &cachegrpc.GrantParams{Service: "s", Grantee: "*"})
+	s.Grant(alice, &cachegrpc.GrantParams{Service: "s", Grantee: "bob"})
+	if _, err := s.GetItem(bob, get); status.Code(err) != codes.PermissionDenied {
+		t.Fatalf("GetItem() after revoking access returned %v, expected PermissionDenied", err)
+	}
+}
@@ -0,0 +1,663 @@
+package server
+
+import (
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/kamenlilovgocourse/gocourse/project/item"
+)
+
+// Kind of change delivered to subscribers. The values match cachegrpc.ItemEvent
+type Event int32
+
+const (
+	EventUpdated Event = iota
+	EventDeleted
+	EventExpired
+	EventEvicted
+	// Sent first to subscriptions asking for the initial value: the current
+	// value of the item, or that it has none
+	EventCurrent
+	EventAbsent
+)
+
+// Condition that must hold for a Set to take effect. The values match
+// cachegrpc.SetCondition
+type Condition int32
+
+const (
+	// Always set the item
+	Always Condition = iota
+	// Only set the item if it has no value yet
+	IfAbsent
+	// Only set the item if its current version is SetOptions.Version
+	IfVersion
+	// Only set the item if its current value is SetOptions.ExpectedValue
+	IfValue
+)
+
+// An Item is the value of a cache entry, with its optional expiry and its
+// version, which changes on every write. The value is a sequence of bytes that
+// need not be valid UTF-8. ContentType and Flags are stored with the value for
+// the clients, the cache does not interpret them
+type Item struct {
+	Value       string
+	Expiry      *time.Time
+	Version     uint64
+	ContentType string
+	Flags       uint32
+}
+
+// SetOptions control how Set writes an item
+type SetOptions struct {
+	Expiry        *time.Time
+	Condition     Condition
+	Version       uint64
+	ExpectedValue string
+	ContentType   string
+	Flags         uint32
+}
+
+// A SetRequest is one of the writes done by SetMulti
+type SetRequest struct {
+	ID    item.ID
+	Value string
+	SetOptions
+}
+
+// An Update is delivered to subscribers when an item changes. For an item that
+// was removed, Item only holds the version of the removed value. Seq numbers the
+// changes of the cache: the changes of an item have increasing, though not
+// consecutive, numbers, which keep increasing across restarts
+type Update struct {
+	ID    item.ID
+	Event Event
+	Item  Item
+	Seq   uint64
+}
+
+// A map entry consists of the value of the cache item, an optional expiry,
+// plus a (empty or nonempty) slice of subscriptions. If subscriptions are
+// present, every time the value is updated or deleted, the subscribers are sent
+// an Update so they can generate a push notification to a connected client. An
+// entry that only exists to hold subscriptions for a key that has no value has
+// Present set to false. Version is taken from a counter of the whole cache on
+// every write and allows clients to perform compare-and-swap updates; it never
+// repeats, even after the item was removed and set again, so an old version can
+// not match a newer value. Generation identifies the write that produced the
+// entry and is unique across the whole cache, so that a pending expiry can tell
+// whether it still applies. LastAccess and Hits track the use of the entry for
+// the eviction policies. Seq is the number of the last change of the entry.
+// Once the entry has been subscribed to, History keeps its last changes, so that
+// subscriptions can be resumed: it holds every change numbered after HistoryFrom
+type mapEntry struct {
+	Value       string
+	ContentType string
+	Flags       uint32
+	Expiry      *time.Time
+	Present     bool
+	Version     uint64
+	Generation  uint64
+	LastAccess  int64
+	Hits        uint64
+	Subs        []*Subscription
+	Seq         uint64
+	History     []Update
+	HistoryFrom uint64
+}
+
+// Return the item held by the entry
+func (e *mapEntry) item() Item {
+	return Item{Value: e.Value, Expiry: e.Expiry, Version: e.Version, ContentType: e.ContentType, Flags: e.Flags}
+}
+
+// A Cache holds items in shards selected by the hash of their IDs, each with its own
+// lock, together with the expiry scheduler, the eviction limits, the subscriptions
+// and the optional persistence of the items. Several caches can be used in one
+// process; CacheServer serves one over gRPC
+type Cache struct {
+	// Updated atomically, kept first for 64-bit alignment
+	nextGeneration uint64
+	nextVersion    uint64
+	nextSeq        uint64
+	itemCount      int64
+	memoryUsed     int64
+	evictions      uint64
+	expirations    uint64
+	subscriptions  int64
+	// Updates dropped from, and subscriptions ended because of, full
+	// subscriber queues
+	droppedUpdates  uint64
+	slowDisconnects uint64
+	// Items and estimated memory by shard
+	shardItems  []int64
+	shardMemory []int64
+	// Set while the cache is over its limits and nothing can be evicted
+	evictStuck int32
+
+	mapsLock []sync.Mutex
+	maps     []map[string]mapEntry
+
+	// Closed by Close, ends the subscriptions and the expiry routine
+	stop      chan struct{}
+	closeOnce sync.Once
+	closeErr  error
+
+	expLock  sync.Mutex
+	expQueue expHeap
+	// Every item has at most one entry in the heap, found via its composed ID
+	expByKey map[string]*expEntry
+	// Signalled when the earliest expiry changes, so the expiry routine can
+	// recompute how long to sleep
+	expWake chan struct{}
+	// Closed when the expiry routine returns
+	expDone chan struct{}
+
+	evictLock         sync.Mutex
+	maxItems          int64
+	maxMemory         int64
+	evictPolicy       EvictionPolicy
+	evictOnlyVolatile bool
+
+	maxValueSize int
+
+	persist *persistence
+
+	patternSubsLock sync.Mutex
+	patternSubs     []*Subscription
+
+	// Every open subscription, item or pattern, so that their queues can be
+	// inspected
+	subsLock sync.Mutex
+	subs     map[*Subscription]bool
+	// Updates a subscriber may fall behind by, and what happens when it does
+	subQueueSize  int
+	slowSubPolicy SlowSubscriberPolicy
+	// Changes kept per subscribed item for resuming subscriptions
+	historySize int
+}
+
+// Create a cache with the default number of shards without starting its expiry
+// routine
+func newCache() *Cache {
+	return newShardedCache(item.IDMapsCount)
+}
+
+func newShardedCache(shards int) *Cache {
+	c := &Cache{
+		// Starting from the time keeps the numbers increasing across restarts
+		nextSeq:      uint64(time.Now().UnixNano()),
+		historySize:  DefaultSubscriberHistory,
+		shardItems:   make([]int64, shards),
+		shardMemory:  make([]int64, shards),
+		mapsLock:     make([]sync.Mutex, shards),
+		maps:         make([]map[string]mapEntry, shards),
+		stop:         make(chan struct{}),
+		expByKey:     make(map[string]*expEntry),
+		expWake:      make(chan struct{}, 1),
+		expDone:      make(chan struct{}),
+		evictPolicy:  lruPolicy{},
+		maxValueSize: DefaultMaxValueSize,
+		subs:         make(map[*Subscription]bool),
+		subQueueSize: DefaultSubscriberQueue,
+	}
+	for i := range c.maps {
+		c.maps[i] = make(map[string]mapEntry)
+	}
+	return c
+}
+
+// New creates an empty cache, and starts removing its items as they expire
+func New() *Cache {
+	return NewSharded(item.IDMapsCount)
+}
+
+// NewSharded creates an empty cache like New, with its items spread over the
+// given number of shards instead of item.IDMapsCount. More shards let more
+// calls on different items run in parallel, at the cost of some memory
+func NewSharded(shards int) *Cache {
+	if shards < 1 {
+		shards = 1
+	}
+	c := newShardedCache(shards)
+	go c.expiryRoutine()
+	return c
+}
+
+// Return the shard that holds the item with the given ID
+func (c *Cache) shardOf(id *item.ID) int {
+	return int(id.Hash() % uint64(len(c.maps)))
+}
+
+// Close ends all subscriptions, stops expiring items and, if persistence is
+// enabled, takes a final snapshot. The cache must not be used afterwards.
+// Calling Close again returns the result of the first call
+func (c *Cache) Close() error {
+	c.closeOnce.Do(func() {
+		close(c.stop)
+		<-c.expDone
+		c.closeErr = c.closePersistence()
+	})
+	return c.closeErr
+}
+
+// Done returns a channel that is closed when the cache is closed
+func (c *Cache) Done() <-chan struct{} {
+	return c.stop
+}
+
+// SetMaxValueSize changes the largest value, in bytes, that can be stored in an
+// item. Must be called before the cache is used
+func (c *Cache) SetMaxValueSize(size int) {
+	c.maxValueSize = size
+}
+
+// Return the item held by a map entry, or an error if the entry holds no value
+func itemOf(id *item.ID, e *mapEntry, ok bool) (Item, error) {
+	if !ok || !e.Present {
+		return Item{}, itemError(id, ErrNotFound, "")
+	}
+	return e.item(), nil
+}
+
+// Check whether the condition of a set holds for the current state of the
+// entry. Must be called with the entry's shard lock held
+func checkSetCondition(id *item.ID, opts *SetOptions, prevMe *mapEntry, found bool) error {
+	present := found && prevMe.Present
+	switch opts.Condition {
+	case IfAbsent:
+		if present {
+			return itemError(id, ErrExists, "")
+		}
+	case IfVersion:
+		if !present || prevMe.Version != opts.Version {
+			return itemError(id, ErrVersionMismatch, fmt.Sprintf("expected %d", opts.Version))
+		}
+	case IfValue:
+		if !present || prevMe.Value != opts.ExpectedValue {
+			return itemError(id, ErrValueMismatch, fmt.Sprintf("expected %q", opts.ExpectedValue))
+		}
+	}
+	return nil
+}
+
+// Apply a set to the shard map, returning the new and the previous state of
+// the entry. The arguments must have been checked with c.validateSet. Must be
+// called with the shard lock held
+func (c *Cache) setLocked(hash int, as *item.ID, value string, opts *SetOptions) (mapEntry, mapEntry, error) {
+	me := mapEntry{Value: value, ContentType: opts.ContentType, Flags: opts.Flags, Expiry: opts.Expiry, Present: true}
+	prevMe, found := c.maps[hash][as.Compose()]
+	if err := checkSetCondition(as, opts, &prevMe, found); err != nil {
+		return mapEntry{}, prevMe, err
+	}
+	if err := c.checkRoom(as.Compose(), &prevMe, &me); err != nil {
+		return mapEntry{}, prevMe, itemError(as, err, "")
+	}
+	if found {
+		me.Subs = prevMe.Subs
+		me.History = prevMe.History
+		me.HistoryFrom = prevMe.HistoryFrom
+	}
+	me.Version = atomic.AddUint64(&c.nextVersion, 1)
+	me.Generation = atomic.AddUint64(&c.nextGeneration, 1)
+	me.Hits = prevMe.Hits
+	touchEntry(&me)
+	c.publish(as, &me, EventUpdated, me.item())
+	c.maps[hash][as.Compose()] = me
+	c.account(hash, as.Compose(), &prevMe, &me)
+	c.logSet(as, &me)
+	return me, prevMe, nil
+}
+
+// Finish a set once the shard lock has been released: schedule or cancel the
+// expiry of the new value, and evict other items if the cache has grown over
+// its limits
+func (c *Cache) afterSet(as *item.ID, me *mapEntry, prevMe *mapEntry) {
+	if me.Expiry != nil {
+		c.scheduleExpiry(as, *me.Expiry, me.Generation)
+	} else if prevMe.Expiry != nil {
+		c.cancelExpiry(as, me.Generation)
+	}
+	c.evictIfNeeded()
+}
+
+// Number a change of an entry, keep it in the history of the entry if it has
+// one, and queue it for the subscribers of the entry and the matching pattern
+// subscribers. Must be called with the shard lock held, so that subscribers
+// receive the changes of an item in order and none is lost or repeated when
+// subscribing; the caller stores the entry
+func (c *Cache) publish(as *item.ID, e *mapEntry, event Event, it Item) {
+	u := Update{ID: *as, Event: event, Item: it, Seq: atomic.AddUint64(&c.nextSeq, 1)}
+	e.Seq = u.Seq
+	if e.History != nil {
+		if len(e.History) >= c.historySize {
+			e.HistoryFrom = e.History[0].Seq
+			copy(e.History, e.History[1:])
+			e.History = e.History[:len(e.History)-1]
+		}
+		e.History = append(e.History, u)
+	}
+	for _, sub := range e.Subs {
+		sub.deliver(u)
+	}
+	c.notifyPatternSubs(u)
+}
+
+// Remove the value of an entry from its shard map, and publish the removal as
+// the given event. If there are subscribers attached to the entry, it is kept
+// without a value so that they still receive later updates. Must be called with
+// the shard lock held
+func (c *Cache) removeEntry(hash int, as *item.ID, e *mapEntry, event Event) {
+	key := as.Compose()
+	c.account(hash, key, e, nil)
+	c.publish(as, e, event, Item{Version: e.Version})
+	if len(e.Subs) > 0 {
+		c.maps[hash][key] = mapEntry{Version: e.Version, Subs: e.Subs, Seq: e.Seq, History: e.History, HistoryFrom: e.HistoryFrom}
+	} else {
+		delete(c.maps[hash], key)
+	}
+}
+
+// Get returns the item with the given ID
+func (c *Cache) Get(id item.ID) (Item, error) {
+	if err := validateID(&id); err != nil {
+		return Item{}, err
+	}
+	hash := c.shardOf(&id)
+	c.mapsLock[hash].Lock()
+	e, ok := c.maps[hash][id.Compose()]
+	if ok && e.Present {
+		touchEntry(&e)
+		c.maps[hash][id.Compose()] = e
+	}
+	c.mapsLock[hash].Unlock()
+	return itemOf(&id, &e, ok)
+}
+
+// Set sets the value of the item with the given ID, if the condition in opts
+// holds, and returns the new state of the item. Every successful set gives the
+// item a new version, and is delivered to its subscribers. The service and
+// name of the ID must not be empty, the value may not be longer than the limit
+// set with SetMaxValueSize, and an expiry must be in the future
+func (c *Cache) Set(id item.ID, value string, opts SetOptions) (Item, error) {
+	if err := c.validateSet(&id, value, &opts); err != nil {
+		return Item{}, err
+	}
+	hash := c.shardOf(&id)
+	c.mapsLock[hash].Lock()
+	me, prevMe, err := c.setLocked(hash, &id, value, &opts)
+	c.mapsLock[hash].Unlock()
+	if err != nil {
+		return Item{}, err
+	}
+	c.afterSet(&id, &me, &prevMe)
+	return me.item(), nil
+}
+
+// Delete removes the item with the given ID, together with any pending expiry for
+// it. Its subscribers are sent a EventDeleted update, and stay subscribed, so that
+// a later Set of the same ID still reaches them
+func (c *Cache) Delete(id item.ID) error {
+	if err := validateID(&id); err != nil {
+		return err
+	}
+	hash := c.shardOf(&id)
+	c.mapsLock[hash].Lock()
+	e, ok := c.maps[hash][id.Compose()]
+	if !ok || !e.Present {
+		c.mapsLock[hash].Unlock()
+		return itemError(&id, ErrNotFound, "")
+	}
+	c.removeEntry(hash, &id, &e, EventDeleted)
+	c.logRemove(opDelete, &id)
+	c.mapsLock[hash].Unlock()
+	if e.Expiry != nil {
+		c.cancelExpiry(&id, e.Generation)
+	}
+	return nil
+}
+
+// A SlowSubscriberPolicy says what happens to a subscriber whose queue of
+// undelivered updates is full when another update arrives
+type SlowSubscriberPolicy int
+
+const (
+	// Drop the oldest queued update to make room, so that the subscriber
+	// skips ahead to the latest values
+	CoalesceSlowSubscribers SlowSubscriberPolicy = iota
+	// End the subscription, see Subscription.Overflowed
+	DisconnectSlowSubscribers
+)
+
+// DefaultSubscriberQueue is how many updates a subscriber may fall behind by
+// unless changed with SetSubscriberQueue
+const DefaultSubscriberQueue = 64
+
+// SlowSubscriberPolicyByName returns the policy called coalesce or disconnect
+func SlowSubscriberPolicyByName(name string) (SlowSubscriberPolicy, error) {
+	switch name {
+	case "coalesce":
+		return CoalesceSlowSubscribers, nil
+	case "disconnect":
+		return DisconnectSlowSubscribers, nil
+	}
+	return 0, fmt.Errorf("unknown slow subscriber policy %q", name)
+}
+
+// DefaultSubscriberHistory is how many changes of a subscribed item are kept for
+// resuming subscriptions unless changed with SetSubscriberHistory
+const DefaultSubscriberHistory = 16
+
+// SetSubscriberHistory changes how many of the last changes of an item are kept
+// once it has been subscribed to, see SubscribeOptions.Resume. They are kept
+// while the item has a value or subscribers. The kept changes hold their values,
+// which are not counted towards the memory limit. Must be called before the
+// cache is used
+func (c *Cache) SetSubscriberHistory(size int) {
+	if size < 1 {
+		size = 1
+	}
+	c.historySize = size
+}
+
+// SetSubscriberQueue changes how many updates a subscriber may fall behind by,
+// and what happens to subscribers that fall further behind. Must be called
+// before the cache is used
+func (c *Cache) SetSubscriberQueue(size int, policy SlowSubscriberPolicy) {
+	if size < 1 {
+		size = 1
+	}
+	c.subQueueSize = size
+	c.slowSubPolicy = policy
+}
+
+// A Subscription receives the changes of one item, or of all items matching a
+// pattern, until it is closed or the cache is closed. Changes are queued for
+// the subscriber, so that writers never wait for it; see SlowSubscriberPolicy
+// for what happens when it does not keep up
+type Subscription struct {
+	cache *Cache
+	id    item.ID
+	// Set for subscriptions to a pattern, which may skip some of the
+	// matching items
+	pattern bool
+	filter  func(*item.ID) bool
+	ch      chan Update
+	// Serializes deliveries, so that dropping the oldest update always makes
+	// room for the next one
+	deliverLock sync.Mutex
+	dropped     uint64
+	// Closed when the queue overflows under DisconnectSlowSubscribers
+	overflow     chan struct{}
+	overflowOnce sync.Once
+	// Closed by Close, so that later deliveries are abandoned
+	done      chan struct{}
+	closeOnce sync.Once
+}
+
+func (c *Cache) newSubscription(id item.ID, pattern bool, filter func(*item.ID) bool) *Subscription {
+	sub := &Subscription{cache: c, id: id, pattern: pattern, filter: filter, ch: make(chan Update, c.subQueueSize),
+		overflow: make(chan struct{}), done: make(chan struct{})}
+	atomic.AddInt64(&c.subscriptions, 1)
+	c.subsLock.Lock()
+	c.subs[sub] = true
+	c.subsLock.Unlock()
+	return sub
+}
+
+// Updates returns the channel on which the changes are delivered
+func (s *Subscription) Updates() <-chan Update {
+	return s.ch
+}
+
+// Overflowed returns a channel that is closed when the subscription is dropped
+// for falling too far behind. Updates are no longer delivered after that
+func (s *Subscription) Overflowed() <-chan struct{} {
+	return s.overflow
+}
+
+// Lag returns the number of updates waiting to be received
+func (s *Subscription) Lag() int {
+	return len(s.ch)
+}
+
+// Dropped returns the number of updates that were skipped because the
+// subscriber did not keep up
+func (s *Subscription) Dropped() uint64 {
+	return atomic.LoadUint64(&s.dropped)
+}
+
+// Queue an update without waiting for the subscriber. When the queue is full,
+// the oldest update is dropped, or the subscription overflows, depending on the
+// policy of the cache
+func (s *Subscription) deliver(u Update) {
+	s.deliverLock.Lock()
+	defer s.deliverLock.Unlock()
+	for {
+		select {
+		case <-s.done:
+			return
+		case <-s.overflow:
+			return
+		case s.ch <- u:
+			return
+		default:
+		}
+		if s.cache.slowSubPolicy == DisconnectSlowSubscribers {
+			s.overflowOnce.Do(func() {
+				atomic.AddUint64(&s.cache.slowDisconnects, 1)
+				close(s.overflow)
+			})
+			return
+		}
+		select {
+		case <-s.ch:
+			atomic.AddUint64(&s.dropped, 1)
+			atomic.AddUint64(&s.cache.droppedUpdates, 1)
+		default:
+		}
+	}
+}
+
+// Close ends the subscription. It may be called more than once
+func (s *Subscription) Close() {
+	s.closeOnce.Do(func() {
+		atomic.AddInt64(&s.cache.subscriptions, -1)
+		s.cache.subsLock.Lock()
+		delete(s.cache.subs, s)
+		s.cache.subsLock.Unlock()
+		if s.pattern {
+			s.cache.removePatternSub(s)
+		} else {
+			s.cache.removeSub(s)
+		}
+		close(s.done)
+	})
+}
+
+// SubscribeOptions say what a new subscription receives before the next change
+type SubscribeOptions struct {
+	// Deliver the current value of the item first, as an EventCurrent update,
+	// or an EventAbsent one if it has no value
+	Initial bool
+	// Deliver the changes numbered after After first, to resume an earlier
+	// subscription whose last update was numbered After. Takes precedence over
+	// Initial
+	Resume bool
+	After  uint64
+}
+
+// Subscribe returns a subscription to the changes of the item with the given ID,
+// whether the item exists or not
+func (c *Cache) Subscribe(id item.ID) *Subscription {
+	sub, _ := c.SubscribeWith(id, SubscribeOptions{})
+	return sub
+}
+
+// SubscribeWith works like Subscribe, and delivers the initial value or the
+// missed changes as asked in opts. Nothing is missed or delivered twice between
+// them and the later changes. Resuming fails with ErrHistoryGone unless every
+// change after opts.After is still kept, see SetSubscriberHistory
+func (c *Cache) SubscribeWith(id item.ID, opts SubscribeOptions) (*Subscription, error) {
+	hash := c.shardOf(&id)
+	c.mapsLock[hash].Lock()
+	defer c.mapsLock[hash].Unlock()
+	e, found := c.maps[hash][id.Compose()]
+	if opts.Resume && (e.History == nil || opts.After < e.HistoryFrom) {
+		return nil, itemError(&id, ErrHistoryGone, fmt.Sprintf("sequence %d", opts.After))
+	}
+	if !found {
+		// Changes of the item before now are not known, so it is as if it was
+		// last changed now
+		e.Seq = atomic.LoadUint64(&c.nextSeq)
+	}
+	if e.History == nil {
+		e.History = make([]Update, 0, c.historySize)
+		e.HistoryFrom = e.Seq
+	}
+	sub := c.newSubscription(id, false, nil)
+	switch {
+	case opts.Resume:
+		for _, u := range e.History {
+			if u.Seq > opts.After {
+				sub.deliver(u)
+			}
+		}
+	case opts.Initial:
+		u := Update{ID: id, Event: EventAbsent, Seq: e.Seq}
+		if e.Present {
+			u.Event = EventCurrent
+			u.Item = e.item()
+		}
+		sub.deliver(u)
+	}
+	e.Subs = append(e.Subs, sub)
+	c.maps[hash][id.Compose()] = e
+	return sub, nil
+}
+
+// Detach a subscription from its item. An entry that was only kept for its
+// subscribers is dropped with the last of them
+func (c *Cache) removeSub(s *Subscription) {
+	hash := c.shardOf(&s.id)
+	key := s.id.Compose()
+	c.mapsLock[hash].Lock()
+	defer c.mapsLock[hash].Unlock()
+	e, found := c.maps[hash][key]
+	if !found {
+		return
+	}
+	for i := range e.Subs {
+		if e.Subs[i] == s {
+			e.Subs = append(e.Subs[:i:i], e.Subs[i+1:]...)
+			break
+		}
+	}
+	if !e.Present && len(e.Subs) == 0 {
+		delete(c.maps[hash], key)
+	} else {
+		c.maps[hash][key] = e
+	}
+}
@@ -0,0 +1,92 @@
+package server
+
+import (
+	"context"
+	"testing"
+
+	"github.com/kamenlilovgocourse/gocourse/project/cachegrpc"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Test that an item deleted and set again does not get a version it had
+// before, so a compare-and-swap with the old version fails
+func TestVersionNotReused(t *testing.T) {
+	s := NewServer(newCache())
+	ctx := context.Background()
+	set := &cachegrpc.SetItemParams{Owner: "o", Service: "s", Name: "n", Value: "v1"}
+	get := &cachegrpc.GetItemParams{Owner: "o", Service: "s", Name: "n"}
+
+	first, err := s.SetItem(ctx, set)
+	if err != nil {
+		t.Fatalf("SetItem() returned error %v", err)
+	}
+	if _, err := s.DeleteItem(ctx, get); err != nil {
+		t.Fatalf("DeleteItem() returned error %v", err)
+	}
+	set.Value = "v2"
+	second, err := s.SetItem(ctx, set)
+	if err != nil {
+		t.Fatalf("SetItem() after DeleteItem() returned error %v", err)
+	}
+	if second.Version <= first.Version {
+		t.Fatalf("SetItem() after DeleteItem() returned version %d, expected more than %d", second.Version, first.Version)
+	}
+
+	_, err = s.SetItem(ctx, &cachegrpc.SetItemParams{Owner: "o", Service: "s", Name: "n", Value: "v3",
+		Condition: cachegrpc.SetCondition_IF_VERSION, Version: first.Version})
+	if status.Code(err) != codes.FailedPrecondition {
+		t.Fatalf("SetItem() with the version from before the delete returned %v, expected FailedPrecondition", err)
+	}
+	if res, err := s.GetItem(ctx, get); err != nil || res.Value != "v2" {
+		t.Fatalf("GetItem() returned %v, %v, expected value v2", res, err)
+	}
+}
+
+// Test that conditional sets only change the item when their condition holds,
+// and fail with FailedPrecondition and a PreconditionFailure otherwise
+func TestSetConditions(t *testing.T) {
+	s := NewServer(newCache())
+	ctx := context.Background()
+	res, err := s.SetItem(ctx, &cachegrpc.SetItemParams{Owner: "o", Service: "s", Name: "n", Value: "v1"})
+	if err != nil {
+		t.Fatalf("SetItem() returned error %v", err)
+	}
+	version := res.Version
+
+	tests := []struct {
+		name string
+		set  *cachegrpc.SetItemParams
+		ok   bool
+	}{
+		{"version mismatch", &cachegrpc.SetItemParams{Value: "x", Condition: cachegrpc.SetCondition_IF_VERSION, Version: version + 1}, false},
+		{"value mismatch", &cachegrpc.SetItemParams{Value: "x", Condition: cachegrpc.SetCondition_IF_VALUE, ExpectedValue: "v2"}, false},
+		{"value match", &cachegrpc.SetItemParams{Value: "v2", Condition: cachegrpc.SetCondition_IF_VALUE, ExpectedValue: "v1"}, true},
+		{"value mismatch after a change", &cachegrpc.SetItemParams{Value: "x", Condition: cachegrpc.SetCondition_IF_VALUE, ExpectedValue: "v1"}, false},
+		{"version from before a change", &cachegrpc.SetItemParams{Value: "x", Condition: cachegrpc.SetCondition_IF_VERSION, Version: version}, false},
+	}
+	value := "v1"
+	for _, test := range tests {
+		test.set.Owner, test.set.Service, test.set.Name = "o", "s", "n"
+		res, err := s.SetItem(ctx, test.set)
+		if test.ok {
+			if err != nil {
+				t.Fatalf("%s returned error %v", test.name, err)
+			}
+			value, version = test.set.Value, res.Version
+		} else {
+			st := status.Convert(err)
+			if st.Code() != codes.FailedPrecondition || len(st.Details()) != 1 {
+				t.Fatalf("%s returned %v, expected FailedPrecondition", test.name, err)
+			}
+			if pf, ok := st.Details()[0].(*errdetails.PreconditionFailure); !ok || pf.Violations[0].Subject != "o:s:n" {
+				t.Fatalf("%s returned detail %v, expected the PreconditionFailure of o:s:n", test.name, st.Details()[0])
+			}
+		}
+		cur, err := s.GetItem(ctx, &cachegrpc.GetItemParams{Owner: "o", Service: "s", Name: "n"})
+		if err != nil || cur.Value != value || cur.Version != version {
+			t.Fatalf("GetItem() after %s returned %v, %v, expected value %s with version %d", test.name, cur, err, value, version)
+		}
+	}
+}
@@ -0,0 +1,88 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strconv"
+	"time"
+
+	"github.com/kamenlilovgocourse/gocourse/project/cachegrpc"
+	"github.com/kamenlilovgocourse/gocourse/project/item"
+)
+
+// Add delta to v, failing instead of wrapping around on overflow
+func addInt64(id *item.ID, v, delta int64) (int64, error) {
+	if (delta > 0 && v > math.MaxInt64-delta) || (delta < 0 && v < math.MinInt64-delta) {
+		return 0, itemError(id, ErrOverflow, fmt.Sprintf("adding %d to %d", delta, v))
+	}
+	return v + delta, nil
+}
+
+// Increment atomically adds a delta to the value of an item, which must hold a
+// 64-bit integer, and returns the new value and version. The read and the write
+// happen under the same shard lock, so concurrent increments never get lost. A
+// missing item is either created from initial and expiry, if create is set, or
+// reported as an error. An existing item keeps its expiry. Subscribers are
+// notified as with Set
+func (c *Cache) Increment(id item.ID, delta int64, create bool, initial int64, expiry *time.Time) (int64, uint64, error) {
+	if err := c.validateSet(&id, "", &SetOptions{Expiry: expiry}); err != nil {
+		return 0, 0, err
+	}
+	hash := c.shardOf(&id)
+	var opts SetOptions
+	var value int64
+	var err error
+	c.mapsLock[hash].Lock()
+	prevMe, found := c.maps[hash][id.Compose()]
+	switch {
+	case found && prevMe.Present:
+		var cur int64
+		cur, err = strconv.ParseInt(prevMe.Value, 10, 64)
+		if err != nil {
+			err = itemError(&id, ErrNotInteger, fmt.Sprintf("%q", prevMe.Value))
+			break
+		}
+		value, err = addInt64(&id, cur, delta)
+		opts.Expiry = prevMe.Expiry
+		opts.ContentType = prevMe.ContentType
+		opts.Flags = prevMe.Flags
+	case create:
+		value, err = addInt64(&id, initial, delta)
+		opts.Expiry = expiry
+	default:
+		err = itemError(&id, ErrNotFound, "")
+	}
+	if err != nil {
+		c.mapsLock[hash].Unlock()
+		return 0, 0, err
+	}
+	me, prevMe, err := c.setLocked(hash, &id, strconv.FormatInt(value, 10), &opts)
+	c.mapsLock[hash].Unlock()
+	if err != nil {
+		return 0, 0, err
+	}
+	c.afterSet(&id, &me, &prevMe)
+	return value, me.Version, nil
+}
+
+// Increment atomically adds a delta to the value of a cache item, which must hold a
+// 64-bit integer, and returns the new value. Concurrent increments from several
+// clients never get lost. A missing item is either created from the initial value
+// and expiry in the call, or reported as an error. An existing item keeps its expiry
+func (s *CacheServer) Increment(ctx context.Context, p *cachegrpc.IncrementParams) (*cachegrpc.IncrementResult, error) {
+	as := item.ID{Owner: p.Owner, Service: p.Service, Name: p.Name}
+	if err := s.checkAccess(ctx, &as, true); err != nil {
+		return nil, err
+	}
+	expiry, err := resolveExpiry(&as, p.Expiry, p.Ttl)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	value, version, err := s.cache.Increment(as, p.Delta, p.Create, p.Initial, expiry)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	s.countSet("Increment")
+	return &cachegrpc.IncrementResult{Value: value, Version: version}, nil
+}
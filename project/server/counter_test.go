@@ -0,0 +1,51 @@
+package server
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/kamenlilovgocourse/gocourse/project/cachegrpc"
+)
+
+// Test that concurrent increments of the same counter are never lost
+func TestIncrementConcurrent(t *testing.T) {
+	s := NewServer(newCache())
+	p := &cachegrpc.IncrementParams{Owner: "o", Service: "s", Name: "counter", Delta: 1, Create: true}
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				if _, err := s.Increment(context.Background(), p); err != nil {
+					t.Errorf("Increment() returned error %v", err)
+					return
+				}
+			}
+		}()
+	}
+	wg.Wait()
+	res, err := s.Increment(context.Background(), &cachegrpc.IncrementParams{Owner: "o", Service: "s", Name: "counter"})
+	if err != nil {
+		t.Fatalf("Increment() of existing counter returned error %v", err)
+	}
+	if res.Value != 2000 {
+		t.Fatalf("Increment() lost updates, counter is %d, expected 2000", res.Value)
+	}
+}
+
+// Test that missing counters are only created on request, and non-numeric
+// values are rejected
+func TestIncrementErrors(t *testing.T) {
+	s := NewServer(newCache())
+	_, err := s.Increment(context.Background(), &cachegrpc.IncrementParams{Owner: "o", Service: "s", Name: "missing", Delta: 1})
+	if err == nil {
+		t.Fatalf("Increment() of missing item without create returned no error")
+	}
+	s.SetItem(context.Background(), &cachegrpc.SetItemParams{Owner: "o", Service: "s", Name: "text", Value: "abc"})
+	_, err = s.Increment(context.Background(), &cachegrpc.IncrementParams{Owner: "o", Service: "s", Name: "text", Delta: 1})
+	if err == nil {
+		t.Fatalf("Increment() of non-numeric item returned no error")
+	}
+}
@@ -0,0 +1,70 @@
+package server
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/kamenlilovgocourse/gocourse/project/cachegrpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// An item stream that passes on what is sent on it
+type recordingItemStream struct {
+	*fakeStream
+	sent chan *cachegrpc.GetItemResult
+}
+
+func (s recordingItemStream) Send(res *cachegrpc.GetItemResult) error {
+	s.sent <- res
+	return nil
+}
+
+// Test that DeleteItem removes an item and reports a second delete as NotFound,
+// and that subscribers are told of the delete and stay subscribed
+func TestDeleteItem(t *testing.T) {
+	s := NewServer(newCache())
+	ctx := context.Background()
+	get := &cachegrpc.GetItemParams{Owner: "o", Service: "s", Name: "n"}
+	if _, err := s.SetItem(ctx, &cachegrpc.SetItemParams{Owner: "o", Service: "s", Name: "n", Value: "1"}); err != nil {
+		t.Fatalf("SetItem() returned error %v", err)
+	}
+
+	subCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	stream := recordingItemStream{&fakeStream{ctx: subCtx}, make(chan *cachegrpc.GetItemResult, 10)}
+	go s.SubscribeItem(&cachegrpc.SubscribeParams{Owner: "o", Service: "s", Name: "n"}, stream)
+	for s.cache.Stats().Subscriptions != 1 {
+		time.Sleep(time.Millisecond)
+	}
+	next := func() *cachegrpc.GetItemResult {
+		select {
+		case res := <-stream.sent:
+			return res
+		case <-time.After(5 * time.Second):
+			t.Fatalf("subscriber received nothing")
+			return nil
+		}
+	}
+
+	if _, err := s.DeleteItem(ctx, get); err != nil {
+		t.Fatalf("DeleteItem() returned error %v", err)
+	}
+	if _, err := s.GetItem(ctx, get); status.Code(err) != codes.NotFound {
+		t.Fatalf("GetItem() of a deleted item returned %v, expected NotFound", err)
+	}
+	if _, err := s.DeleteItem(ctx, get); status.Code(err) != codes.NotFound {
+		t.Fatalf("second DeleteItem() returned %v, expected NotFound", err)
+	}
+	if res := next(); res.Event != cachegrpc.ItemEvent_DELETED {
+		t.Fatalf("subscriber received event %v, expected DELETED", res.Event)
+	}
+
+	if _, err := s.SetItem(ctx, &cachegrpc.SetItemParams{Owner: "o", Service: "s", Name: "n", Value: "2"}); err != nil {
+		t.Fatalf("SetItem() after DeleteItem() returned error %v", err)
+	}
+	if res := next(); res.Event != cachegrpc.ItemEvent_UPDATED || res.Value != "2" {
+		t.Fatalf("subscriber received %v after the item was set again, expected value 2", res)
+	}
+}
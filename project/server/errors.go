@@ -0,0 +1,158 @@
+package server
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/kamenlilovgocourse/gocourse/project/item"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Reasons for which a cache operation fails. Errors returned by Cache wrap one
+// of them in an *Error, so callers can test for them with errors.Is
+var (
+	ErrNotFound        = errors.New("item not found")
+	ErrExists          = errors.New("item already exists")
+	ErrVersionMismatch = errors.New("item version does not match")
+	ErrValueMismatch   = errors.New("item value does not match")
+	ErrNotInteger      = errors.New("item value is not an integer")
+	ErrOverflow        = errors.New("counter would overflow")
+	ErrInvalidID       = errors.New("service and name must not be empty")
+	ErrColonInOwner    = errors.New("owner must not contain ':'")
+	ErrColonInService  = errors.New("service must not contain ':'")
+	ErrExpiryInPast    = errors.New("expiry is in the past")
+	ErrInvalidTTL      = errors.New("ttl must be positive")
+	ErrExpiryAndTTL    = errors.New("only one of expiry and ttl may be set")
+	ErrNoExpiry        = errors.New("expiry or ttl must be set")
+	ErrValueTooLarge   = errors.New("value is too large")
+	ErrCacheFull       = errors.New("cache is full")
+	ErrHistoryGone     = errors.New("changes since the given sequence are no longer kept")
+)
+
+// DefaultMaxValueSize is the largest value, in bytes, that can be stored in an
+// item unless changed with SetMaxValueSize
+const DefaultMaxValueSize = 1 << 20
+
+// An Error reports why an operation on an item failed. Err is one of the Err*
+// values, and Detail, if not empty, says more about the failure
+type Error struct {
+	ID     item.ID
+	Err    error
+	Detail string
+}
+
+func (e *Error) Error() string {
+	msg := e.Err.Error() + ": " + e.ID.Compose()
+	if e.Detail != "" {
+		msg += ", " + e.Detail
+	}
+	return msg
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+func itemError(id *item.ID, err error, detail string) error {
+	return &Error{ID: *id, Err: err, Detail: detail}
+}
+
+// Check that an item ID names an item, rather than a pattern or nothing. The
+// owner and service may not contain the ':' that separates the parts of the
+// composed key, so that every key can be parsed back into the ID it was made of
+func validateID(id *item.ID) error {
+	switch {
+	case id.Service == "" || id.Name == "":
+		return itemError(id, ErrInvalidID, "")
+	case strings.Contains(id.Owner, ":"):
+		return itemError(id, ErrColonInOwner, "")
+	case strings.Contains(id.Service, ":"):
+		return itemError(id, ErrColonInService, "")
+	}
+	return nil
+}
+
+// Check the arguments of a set before the shard is locked
+func (c *Cache) validateSet(id *item.ID, value string, opts *SetOptions) error {
+	if err := validateID(id); err != nil {
+		return err
+	}
+	if len(value) > c.maxValueSize {
+		return itemError(id, ErrValueTooLarge, fmt.Sprintf("%d bytes, the limit is %d", len(value), c.maxValueSize))
+	}
+	if opts.Expiry != nil && !opts.Expiry.After(time.Now()) {
+		return itemError(id, ErrExpiryInPast, opts.Expiry.UTC().Format(time.RFC3339))
+	}
+	return nil
+}
+
+// The gRPC status code for each of the Err* values, and the request field that
+// was wrong for those caused by a bad argument
+var errorCodes = map[error]struct {
+	code  codes.Code
+	field string
+}{
+	ErrNotFound:        {codes.NotFound, ""},
+	ErrExists:          {codes.FailedPrecondition, ""},
+	ErrVersionMismatch: {codes.FailedPrecondition, ""},
+	ErrValueMismatch:   {codes.FailedPrecondition, ""},
+	ErrNotInteger:      {codes.FailedPrecondition, ""},
+	ErrOverflow:        {codes.OutOfRange, "delta"},
+	ErrInvalidID:       {codes.InvalidArgument, "name"},
+	ErrColonInOwner:    {codes.InvalidArgument, "owner"},
+	ErrColonInService:  {codes.InvalidArgument, "service"},
+	ErrExpiryInPast:    {codes.InvalidArgument, "expiry"},
+	ErrInvalidTTL:      {codes.InvalidArgument, "ttl"},
+	ErrExpiryAndTTL:    {codes.InvalidArgument, "ttl"},
+	ErrNoExpiry:        {codes.InvalidArgument, "ttl"},
+	ErrValueTooLarge:   {codes.InvalidArgument, "value"},
+	ErrCacheFull:       {codes.ResourceExhausted, ""},
+	ErrHistoryGone:     {codes.OutOfRange, "from_sequence"},
+}
+
+// Convert an error returned by Cache to a gRPC status error, with details
+// describing the failure: ResourceInfo for missing items, BadRequest for bad
+// arguments, PreconditionFailure for unmet conditions and QuotaFailure when the
+// cache is full. Errors that already are status errors are returned as they are
+func toStatus(err error) error {
+	if err == nil {
+		return nil
+	}
+	if _, ok := status.FromError(err); ok {
+		return err
+	}
+	var ie *Error
+	if !errors.As(err, &ie) {
+		return status.Error(codes.Internal, err.Error())
+	}
+	ec, found := errorCodes[ie.Err]
+	if !found {
+		return status.Error(codes.Internal, err.Error())
+	}
+	st := status.New(ec.code, err.Error())
+	var detail *status.Status
+	switch {
+	case ec.code == codes.NotFound:
+		detail, _ = st.WithDetails(&errdetails.ResourceInfo{ResourceType: "item", ResourceName: ie.ID.Compose(), Description: ie.Err.Error()})
+	case ec.field != "":
+		detail, _ = st.WithDetails(&errdetails.BadRequest{FieldViolations: []*errdetails.BadRequest_FieldViolation{
+			{Field: ec.field, Description: ie.Err.Error()},
+		}})
+	case ec.code == codes.FailedPrecondition:
+		detail, _ = st.WithDetails(&errdetails.PreconditionFailure{Violations: []*errdetails.PreconditionFailure_Violation{
+			{Type: "CONDITION", Subject: ie.ID.Compose(), Description: ie.Err.Error()},
+		}})
+	case ec.code == codes.ResourceExhausted:
+		detail, _ = st.WithDetails(&errdetails.QuotaFailure{Violations: []*errdetails.QuotaFailure_Violation{
+			{Subject: ie.ID.Compose(), Description: ie.Err.Error()},
+		}})
+	}
+	if detail != nil {
+		st = detail
+	}
+	return st.Err()
+}
@@ -0,0 +1,90 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/kamenlilovgocourse/gocourse/project/cachegrpc"
+	"github.com/kamenlilovgocourse/gocourse/project/item"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// Test that failures reach clients with the right status code, and that the
+// engine errors can be told apart with errors.Is
+func TestErrorCodes(t *testing.T) {
+	c := newCache()
+	s := NewServer(c)
+	ctx := context.Background()
+	past := timestamppb.New(time.Now().Add(-time.Minute))
+	if _, err := s.SetItem(ctx, &cachegrpc.SetItemParams{Owner: "o", Service: "s", Name: "n", Value: "v"}); err != nil {
+		t.Fatalf("SetItem() returned error %v", err)
+	}
+
+	tests := []struct {
+		name string
+		call func() error
+		code codes.Code
+	}{
+		{"missing item", func() error {
+			_, err := s.GetItem(ctx, &cachegrpc.GetItemParams{Owner: "o", Service: "s", Name: "missing"})
+			return err
+		}, codes.NotFound},
+		{"empty name", func() error {
+			_, err := s.SetItem(ctx, &cachegrpc.SetItemParams{Owner: "o", Service: "s", Value: "v"})
+			return err
+		}, codes.InvalidArgument},
+		{"colon in owner", func() error {
+			_, err := s.SetItem(ctx, &cachegrpc.SetItemParams{Owner: "team:a", Service: "s", Name: "n", Value: "v"})
+			return err
+		}, codes.InvalidArgument},
+		{"colon in service", func() error {
+			_, err := s.GetItem(ctx, &cachegrpc.GetItemParams{Owner: "o", Service: "s:t", Name: "n"})
+			return err
+		}, codes.InvalidArgument},
+		{"expiry in the past", func() error {
+			_, err := s.SetItem(ctx, &cachegrpc.SetItemParams{Owner: "o", Service: "s", Name: "n", Value: "v", Expiry: past})
+			return err
+		}, codes.InvalidArgument},
+		{"oversize value", func() error {
+			_, err := s.SetItem(ctx, &cachegrpc.SetItemParams{Owner: "o", Service: "s", Name: "n", Value: strings.Repeat("x", DefaultMaxValueSize+1)})
+			return err
+		}, codes.InvalidArgument},
+		{"add of existing item", func() error {
+			_, err := s.SetItem(ctx, &cachegrpc.SetItemParams{Owner: "o", Service: "s", Name: "n", Value: "v", Condition: cachegrpc.SetCondition_IF_ABSENT})
+			return err
+		}, codes.FailedPrecondition},
+		{"increment of text", func() error {
+			_, err := s.Increment(ctx, &cachegrpc.IncrementParams{Owner: "o", Service: "s", Name: "n", Delta: 1})
+			return err
+		}, codes.FailedPrecondition},
+	}
+	for _, test := range tests {
+		if err := test.call(); status.Code(err) != test.code {
+			t.Fatalf("%s returned %v, expected %v", test.name, err, test.code)
+		}
+	}
+
+	_, err := s.GetItem(ctx, &cachegrpc.GetItemParams{Owner: "o", Service: "s", Name: "missing"})
+	details := status.Convert(err).Details()
+	if len(details) != 1 {
+		t.Fatalf("NotFound error carries %d details, expected 1", len(details))
+	}
+	if info, ok := details[0].(*errdetails.ResourceInfo); !ok || info.ResourceName != "o:s:missing" {
+		t.Fatalf("NotFound error carries detail %v, expected the ResourceInfo of o:s:missing", details[0])
+	}
+	if _, err := c.Get(item.ID{Owner: "o", Service: "s", Name: "missing"}); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("Get() of a missing item returned %v, expected ErrNotFound", err)
+	}
+
+	c.SetEvictionLimits(0, 100, lruPolicy{}, false)
+	_, err = s.SetItem(ctx, &cachegrpc.SetItemParams{Owner: "o", Service: "s", Name: "big", Value: strings.Repeat("x", 100)})
+	if status.Code(err) != codes.ResourceExhausted {
+		t.Fatalf("SetItem() of an item larger than the memory limit returned %v, expected ResourceExhausted", err)
+	}
+}
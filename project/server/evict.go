@@ -0,0 +1,198 @@
+package server
+
+import (
+	"errors"
+	"log"
+	"math/rand"
+	"sync/atomic"
+	"time"
+
+	"github.com/kamenlilovgocourse/gocourse/project/item"
+)
+
+// Rough per-item overhead of a map entry, on top of the key and value bytes,
+// used to estimate the memory taken by the cache
+const entryOverhead = 128
+
+// How many entries are compared to pick each item to evict. Like memcached and
+// redis, the policy is applied to a random sample instead of all the items, so
+// eviction does not need to keep all items in a global order
+var evictionSamples = 5
+
+// An EvictionPolicy decides which items to evict first when the cache is over
+// its limits
+type EvictionPolicy interface {
+	// Before reports whether a should be evicted before b
+	Before(a, b *mapEntry) bool
+}
+
+// Evict the least recently used item first
+type lruPolicy struct{}
+
+func (lruPolicy) Before(a, b *mapEntry) bool { return a.LastAccess < b.LastAccess }
+
+// Evict the least frequently used item first, and of equally used items the
+// least recently used one
+type lfuPolicy struct{}
+
+func (lfuPolicy) Before(a, b *mapEntry) bool {
+	if a.Hits != b.Hits {
+		return a.Hits < b.Hits
+	}
+	return a.LastAccess < b.LastAccess
+}
+
+// Evict any of the sampled items
+type randomPolicy struct{}
+
+func (randomPolicy) Before(a, b *mapEntry) bool { return false }
+
+// EvictionPolicyByName returns one of the built-in eviction policies: lru, lfu
+// or random
+func EvictionPolicyByName(name string) (EvictionPolicy, error) {
+	switch name {
+	case "lru":
+		return lruPolicy{}, nil
+	case "lfu":
+		return lfuPolicy{}, nil
+	case "random":
+		return randomPolicy{}, nil
+	}
+	return nil, errors.New("unknown eviction policy " + name)
+}
+
+// SetEvictionLimits limits the number of items and the estimated memory used by
+// the cache; zero means no limit. When a write takes the cache over a limit,
+// items chosen by policy are evicted until it is back within the limits. If
+// volatileOnly is set, only items with an expiry are ever evicted. Must be called
+// before the cache is used
+func (c *Cache) SetEvictionLimits(items int64, memory int64, policy EvictionPolicy, volatileOnly bool) {
+	c.maxItems = items
+	c.maxMemory = memory
+	c.evictPolicy = policy
+	c.evictOnlyVolatile = volatileOnly
+}
+
+// Evictions returns the number of items evicted since the cache was created
+func (c *Cache) Evictions() uint64 {
+	return atomic.LoadUint64(&c.evictions)
+}
+
+func entrySize(key string, e *mapEntry) int64 {
+	return int64(len(key) + len(e.Value) + len(e.ContentType) + entryOverhead)
+}
+
+// Adjust the item count and memory estimate, of the cache and of the shard
+// hash, when the entry stored under key changes from prev to me. Either may be
+// nil, or an entry without a value
+func (c *Cache) account(hash int, key string, prev *mapEntry, me *mapEntry) {
+	if prev != nil && prev.Present {
+		size := entrySize(key, prev)
+		atomic.AddInt64(&c.itemCount, -1)
+		atomic.AddInt64(&c.memoryUsed, -size)
+		atomic.AddInt64(&c.shardItems[hash], -1)
+		atomic.AddInt64(&c.shardMemory[hash], -size)
+	}
+	if me != nil && me.Present {
+		size := entrySize(key, me)
+		atomic.AddInt64(&c.itemCount, 1)
+		atomic.AddInt64(&c.memoryUsed, size)
+		atomic.AddInt64(&c.shardItems[hash], 1)
+		atomic.AddInt64(&c.shardMemory[hash], size)
+	}
+}
+
+// Record a read or write of an entry, for the LRU and LFU policies
+func touchEntry(e *mapEntry) {
+	e.LastAccess = time.Now().UnixNano()
+	e.Hits++
+}
+
+// Refuse a write that would take the cache over its memory limit on its own, or
+// that would grow a cache that is over its limits with nothing left to evict
+func (c *Cache) checkRoom(key string, prev *mapEntry, me *mapEntry) error {
+	size := entrySize(key, me)
+	if c.maxMemory > 0 && size > c.maxMemory {
+		return ErrCacheFull
+	}
+	grows := !prev.Present || size > entrySize(key, prev)
+	if grows && atomic.LoadInt32(&c.evictStuck) != 0 && c.overLimits() {
+		return ErrCacheFull
+	}
+	return nil
+}
+
+func (c *Cache) overLimits() bool {
+	return (c.maxItems > 0 && atomic.LoadInt64(&c.itemCount) > c.maxItems) ||
+		(c.maxMemory > 0 && atomic.LoadInt64(&c.memoryUsed) > c.maxMemory)
+}
+
+// An entry sampled as a candidate for eviction
+type evictCandidate struct {
+	hash int
+	key  string
+	me   mapEntry
+}
+
+// Sample entries from consecutive shards, starting at a random one, and return
+// the one the eviction policy would evict first
+func (c *Cache) pickVictim() (evictCandidate, bool) {
+	var best evictCandidate
+	found := false
+	sampled := 0
+	start := rand.Intn(len(c.maps))
+	for i := 0; i < len(c.maps) && sampled < evictionSamples; i++ {
+		hash := (start + i) % len(c.maps)
+		c.mapsLock[hash].Lock()
+		// Map iteration order is random, so the first entries are a random sample
+		for key, me := range c.maps[hash] {
+			if !me.Present || (c.evictOnlyVolatile && me.Expiry == nil) {
+				continue
+			}
+			if !found || c.evictPolicy.Before(&me, &best.me) {
+				best = evictCandidate{hash: hash, key: key, me: me}
+				found = true
+			}
+			sampled++
+			break
+		}
+		c.mapsLock[hash].Unlock()
+	}
+	return best, found
+}
+
+// Evict items until the cache is within its limits again. Called after every
+// write, without any shard lock held. Only one goroutine evicts at a time;
+// writers that find eviction already running leave the work to it
+func (c *Cache) evictIfNeeded() {
+	if !c.overLimits() || !c.evictLock.TryLock() {
+		return
+	}
+	defer c.evictLock.Unlock()
+	for c.overLimits() {
+		victim, found := c.pickVictim()
+		if !found {
+			if atomic.SwapInt32(&c.evictStuck, 1) == 0 {
+				log.Printf("Cache is over its limits, but there is no item that can be evicted\n")
+			}
+			return
+		}
+		as := item.ID{}
+		as.Parse(victim.key)
+		c.mapsLock[victim.hash].Lock()
+		me, ok := c.maps[victim.hash][victim.key]
+		if !ok || !me.Present || me.Generation != victim.me.Generation {
+			// Changed since it was sampled, pick again
+			c.mapsLock[victim.hash].Unlock()
+			continue
+		}
+		c.removeEntry(victim.hash, &as, &me, EventEvicted)
+		c.logRemove(opEvict, &as)
+		c.mapsLock[victim.hash].Unlock()
+		atomic.AddUint64(&c.evictions, 1)
+		if me.Expiry != nil {
+			c.cancelExpiry(&as, me.Generation)
+		}
+	}
+	atomic.StoreInt32(&c.evictStuck, 0)
+}
@@ -0,0 +1,52 @@
+package server
+
+import (
+	"context"
+	"testing"
+
+	"github.com/kamenlilovgocourse/gocourse/project/cachegrpc"
+	"github.com/kamenlilovgocourse/gocourse/project/item"
+)
+
+// Test that going over the item limit evicts the least recently used item, and
+// that its subscribers are told it was evicted
+func TestEvictLRU(t *testing.T) {
+	c := newCache()
+	s := NewServer(c)
+	c.SetEvictionLimits(3, 0, lruPolicy{}, false)
+	// Sample every shard, so the victim does not depend on the random sample
+	defer func(n int) { evictionSamples = n }(evictionSamples)
+	evictionSamples = 1 << 30
+
+	set := func(name string) {
+		_, err := s.SetItem(context.Background(), &cachegrpc.SetItemParams{Owner: "evict", Service: "s", Name: name, Value: name})
+		if err != nil {
+			t.Fatalf("SetItem(%s) returned error %v", name, err)
+		}
+	}
+	get := func(name string) error {
+		_, err := s.GetItem(context.Background(), &cachegrpc.GetItemParams{Owner: "evict", Service: "s", Name: name})
+		return err
+	}
+	set("a")
+	set("b")
+	set("c")
+	get("a")
+
+	sub := c.Subscribe(item.ID{Owner: "evict", Service: "s", Name: "b"})
+	set("d")
+	if c.Evictions() != 1 {
+		t.Fatalf("setting an item over the limit evicted %d items, expected 1", c.Evictions())
+	}
+	if get("b") == nil {
+		t.Fatalf("the least recently used item was not evicted")
+	}
+	for _, name := range []string{"a", "c", "d"} {
+		if err := get(name); err != nil {
+			t.Fatalf("item %s was evicted instead of the least recently used one", name)
+		}
+	}
+	if u := <-sub.ch; u.Event != EventEvicted {
+		t.Fatalf("subscriber received event %v, expected EventEvicted", u.Event)
+	}
+}
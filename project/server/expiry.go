@@ -1,82 +1,144 @@
 package server
 
 import (
+	"container/heap"
 	"log"
-	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/kamenlilovgocourse/gocourse/project/item"
 )
 
-type expListEntry struct {
-	next   *expListEntry
-	ID     item.ID
-	Expiry time.Time
+// An item waiting to expire. Generation identifies the write that set the
+// expiry, so that an expiry never removes a value written after it. index is
+// the position of the entry in the expiry heap, maintained by the heap.Interface
+// methods so that the entry can be moved or removed when the item's expiry changes
+type expEntry struct {
+	ID         item.ID
+	Expiry     time.Time
+	Generation uint64
+	index      int
 }
 
-var (
-	expListLock                sync.Mutex
-	expList                    *expListEntry
-	InsertThreadShutdown       sync.WaitGroup
-	NotifyInsertThreadShutdown chan struct{}
-)
+// A min-heap of expiry entries ordered by expiry time, so that the item that
+// expires first is always at index 0
+type expHeap []*expEntry
+
+func (h expHeap) Len() int           { return len(h) }
+func (h expHeap) Less(i, j int) bool { return h[i].Expiry.Before(h[j].Expiry) }
+
+func (h expHeap) Swap(i, j int) {
+	h[i], h[j] = h[j], h[i]
+	h[i].index = i
+	h[j].index = j
+}
+
+func (h *expHeap) Push(x any) {
+	e := x.(*expEntry)
+	e.index = len(*h)
+	*h = append(*h, e)
+}
 
-func insertInExpList(Id *item.ID, exp time.Time) {
-	expListLock.Lock()
-	defer expListLock.Unlock()
-	var prevl *expListEntry = nil
-	l := expList
-	for l != nil {
-		if exp.After(l.Expiry) {
-			prevl = l
-			l = l.next
-			continue
+func (h *expHeap) Pop() any {
+	old := *h
+	n := len(old)
+	e := old[n-1]
+	old[n-1] = nil
+	e.index = -1
+	*h = old[:n-1]
+	return e
+}
+
+// Schedule the value written with the given generation of the item with the given
+// ID to expire at exp. If the item was already scheduled to expire, its previous
+// expiry is replaced, unless it belongs to a later write. Writes are scheduled
+// after the shard lock is released, so concurrent writers may get here out of order
+func (c *Cache) scheduleExpiry(Id *item.ID, exp time.Time, gen uint64) {
+	c.expLock.Lock()
+	defer c.expLock.Unlock()
+	key := Id.Compose()
+	e, found := c.expByKey[key]
+	if found {
+		if e.Generation > gen {
+			return
 		}
-		// This time is before the list item, insert it here
-		newEntry := expListEntry{ID: *Id, Expiry: exp, next: l}
-		if prevl == nil {
-			expList = &newEntry
-		} else {
-			prevl.next = &newEntry
+		e.Expiry = exp
+		e.Generation = gen
+		heap.Fix(&c.expQueue, e.index)
+	} else {
+		e = &expEntry{ID: *Id, Expiry: exp, Generation: gen}
+		heap.Push(&c.expQueue, e)
+		c.expByKey[key] = e
+	}
+	if c.expQueue[0] == e {
+		select {
+		case c.expWake <- struct{}{}:
+		default:
 		}
+	}
+}
+
+// Remove the pending expiry of the item with the given ID, if any, unless it
+// was set by a write later than generation gen. Used when the item is deleted,
+// or overwritten without an expiry
+func (c *Cache) cancelExpiry(Id *item.ID, gen uint64) {
+	c.expLock.Lock()
+	defer c.expLock.Unlock()
+	key := Id.Compose()
+	e, found := c.expByKey[key]
+	if !found || e.Generation > gen {
 		return
 	}
-	// This time should go at the end of the list
-	newEntry := expListEntry{ID: *Id, Expiry: exp, next: nil}
-	if prevl == nil {
-		expList = &newEntry
-	} else {
-		prevl.next = &newEntry
+	heap.Remove(&c.expQueue, e.index)
+	delete(c.expByKey, key)
+}
+
+// Remove all items whose expiry time is not after now from the cache, and
+// return how long to wait until the next item expires. An item is only removed
+// if its current value is still the one written together with the expiry.
+// Subscribers of removed items are sent an EventExpired update and stay subscribed
+func (c *Cache) removeExpired(now time.Time) time.Duration {
+	wait := time.Hour
+	c.expLock.Lock()
+	for len(c.expQueue) > 0 {
+		e := c.expQueue[0]
+		if e.Expiry.After(now) {
+			// No need to scan further, there's yet time for this item
+			wait = e.Expiry.Sub(now)
+			break
+		}
+		heap.Pop(&c.expQueue)
+		delete(c.expByKey, e.ID.Compose())
+		hash := c.shardOf(&e.ID)
+		c.mapsLock[hash].Lock()
+		me, found := c.maps[hash][e.ID.Compose()]
+		if found && me.Present && me.Generation == e.Generation {
+			log.Printf("Removing stale item %s at %v\n", e.ID.Compose(), now)
+			c.removeEntry(hash, &e.ID, &me, EventExpired)
+			c.logRemove(opExpire, &e.ID)
+			atomic.AddUint64(&c.expirations, 1)
+		}
+		c.mapsLock[hash].Unlock()
 	}
+	c.expLock.Unlock()
+	return wait
 }
 
-func ScanExpListRoutine() {
+// Remove items from the cache as they expire. Sleeps until the earliest pending
+// expiry, or until woken up because an earlier one was scheduled, and keeps
+// running until the cache is closed
+func (c *Cache) expiryRoutine() {
+	defer close(c.expDone)
 	for {
-		time.Sleep(time.Second)
-		now := time.Now().UTC()
-		expListLock.Lock()
-		for expList != nil {
-			if expList.Expiry.After(now) {
-				// No need to scan further, there's yet time for this item
-				break
-			}
-			// Remove this item
-			log.Printf("Removing stale item %s at %v\n", expList.ID.Compose(), now)
-			as := item.ID{Owner: expList.ID.Owner, Service: expList.ID.Service, Name: expList.ID.Name}
-			hash := as.HashKey()
-			mapsLock[hash].Lock()
-			delete(maps[hash], as.Compose())
-			mapsLock[hash].Unlock()
-			expList = expList.next
-			// Rescan, maybe more items are expired
-		}
-		expListLock.Unlock()
+		wait := c.removeExpired(time.Now().UTC())
+		timer := time.NewTimer(wait)
 		select {
-		case <-NotifyInsertThreadShutdown:
-			InsertThreadShutdown.Done()
+		case <-timer.C:
+		case <-c.expWake:
+			timer.Stop()
+		case <-c.stop:
+			timer.Stop()
 			return
-		default:
-			continue
 		}
 	}
 }
@@ -0,0 +1,329 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/kamenlilovgocourse/gocourse/project/cachegrpc"
+	"github.com/kamenlilovgocourse/gocourse/project/item"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// Test that scheduleExpiry keeps the earliest expiry at the top of the heap,
+// and that rescheduling and cancelling an item do not leave stale entries
+func TestScheduleExpiry(t *testing.T) {
+	cache := newCache()
+	now := time.Now().UTC()
+	a := item.ID{Owner: "o", Service: "s", Name: "a"}
+	b := item.ID{Owner: "o", Service: "s", Name: "b"}
+	c := item.ID{Owner: "o", Service: "s", Name: "c"}
+	cache.scheduleExpiry(&a, now.Add(3*time.Second), 1)
+	cache.scheduleExpiry(&b, now.Add(time.Second), 2)
+	cache.scheduleExpiry(&c, now.Add(2*time.Second), 3)
+	if cache.expQueue[0].ID != b {
+		t.Fatalf("scheduleExpiry() did not put the earliest item first")
+	}
+
+	cache.scheduleExpiry(&b, now.Add(4*time.Second), 4)
+	if len(cache.expQueue) != 3 {
+		t.Fatalf("scheduleExpiry() of an already scheduled item added a second entry")
+	}
+	if cache.expQueue[0].ID != c {
+		t.Fatalf("scheduleExpiry() with a later expiry did not move the item back")
+	}
+
+	cache.cancelExpiry(&c, 5)
+	if len(cache.expQueue) != 2 || cache.expQueue[0].ID != a {
+		t.Fatalf("cancelExpiry() did not remove the item")
+	}
+	cache.cancelExpiry(&c, 6)
+	if len(cache.expQueue) != 2 {
+		t.Fatalf("cancelExpiry() of an item without expiry changed the heap")
+	}
+}
+
+// Test that removeExpired deletes exactly the items that are due, and reports
+// the time until the next one
+func TestRemoveExpired(t *testing.T) {
+	c := newCache()
+	now := time.Now().UTC()
+	ids := []item.ID{
+		{Owner: "o", Service: "s", Name: "expired1"},
+		{Owner: "o", Service: "s", Name: "expired2"},
+		{Owner: "o", Service: "s", Name: "pending"},
+	}
+	expiries := []time.Time{now.Add(-time.Second), now, now.Add(500 * time.Millisecond)}
+	for i := range ids {
+		hash := c.shardOf(&ids[i])
+		me := mapEntry{Value: "v", Expiry: &expiries[i], Present: true, Generation: uint64(i + 1)}
+		c.maps[hash][ids[i].Compose()] = me
+		c.account(hash, ids[i].Compose(), nil, &me)
+		c.scheduleExpiry(&ids[i], expiries[i], uint64(i+1))
+	}
+	wait := c.removeExpired(now)
+	if wait != 500*time.Millisecond {
+		t.Fatalf("removeExpired() returned wait %v, expected 500ms", wait)
+	}
+	for i, id := range ids {
+		_, found := c.maps[c.shardOf(&id)][id.Compose()]
+		if found != (i == 2) {
+			t.Fatalf("removeExpired() left wrong presence %v for %s", found, id.Compose())
+		}
+	}
+}
+
+// Set an item through the server API, with an optional expiry
+func setWithExpiry(t *testing.T, c *Cache, id item.ID, value string, exp *time.Time) {
+	p := &cachegrpc.SetItemParams{Owner: id.Owner, Service: id.Service, Name: id.Name, Value: value}
+	if exp != nil {
+		p.Expiry = timestamppb.New(*exp)
+	}
+	if _, err := NewServer(c).SetItem(context.Background(), p); err != nil {
+		t.Fatalf("SetItem(%s) returned error %v", id.Compose(), err)
+	}
+}
+
+// Return the current value of an item, and whether it is present
+func getValue(c *Cache, id item.ID) (string, bool) {
+	res, err := NewServer(c).GetItem(context.Background(), &cachegrpc.GetItemParams{Owner: id.Owner, Service: id.Service, Name: id.Name})
+	if err != nil {
+		return "", false
+	}
+	return res.Value, true
+}
+
+// Test that re-setting an item with an earlier expiry replaces the expiry of
+// the previous value
+func TestExpiryOverwrite(t *testing.T) {
+	c := newCache()
+	id := item.ID{Owner: "o", Service: "s", Name: "overwrite"}
+	now := time.Now().UTC()
+	exp1 := now.Add(30 * time.Second)
+	exp2 := now.Add(10 * time.Second)
+	setWithExpiry(t, c, id, "first", &exp1)
+	setWithExpiry(t, c, id, "second", &exp2)
+	c.removeExpired(now.Add(5 * time.Second))
+	value, found := getValue(c, id)
+	if !found || value != "second" {
+		t.Fatalf("item was removed before its new expiry")
+	}
+	c.removeExpired(now.Add(20 * time.Second))
+	if _, found := getValue(c, id); found {
+		t.Fatalf("item was not removed at its new expiry")
+	}
+	if len(c.expQueue) != 0 {
+		t.Fatalf("overwrite left the expiry of the previous value pending")
+	}
+}
+
+// Test that re-setting an item without an expiry cancels the expiry of the
+// previous value
+func TestExpiryClear(t *testing.T) {
+	c := newCache()
+	id := item.ID{Owner: "o", Service: "s", Name: "clear"}
+	now := time.Now().UTC()
+	exp := now.Add(10 * time.Second)
+	setWithExpiry(t, c, id, "first", &exp)
+	setWithExpiry(t, c, id, "second", nil)
+	c.removeExpired(now.Add(20 * time.Second))
+	value, found := getValue(c, id)
+	if !found || value != "second" {
+		t.Fatalf("expiry of overwritten value removed the new value")
+	}
+	if len(c.expQueue) != 0 {
+		t.Fatalf("overwrite without expiry left a pending expiry")
+	}
+}
+
+// Test that re-setting an item with a later expiry extends its lifetime, and
+// the new expiry still removes it
+func TestExpiryExtend(t *testing.T) {
+	c := newCache()
+	id := item.ID{Owner: "o", Service: "s", Name: "extend"}
+	now := time.Now().UTC()
+	exp1 := now.Add(10 * time.Second)
+	exp2 := now.Add(30 * time.Second)
+	setWithExpiry(t, c, id, "first", &exp1)
+	setWithExpiry(t, c, id, "second", &exp2)
+	c.removeExpired(now.Add(20 * time.Second))
+	value, found := getValue(c, id)
+	if !found || value != "second" {
+		t.Fatalf("item was removed at its original expiry after being extended")
+	}
+	c.removeExpired(now.Add(40 * time.Second))
+	if _, found := getValue(c, id); found {
+		t.Fatalf("item was not removed at its extended expiry")
+	}
+}
+
+// Test that an expiry scheduled by a write that lost a race with a later write
+// does not remove or replace the later value and its expiry
+func TestExpiryOutOfOrder(t *testing.T) {
+	c := newCache()
+	id := item.ID{Owner: "o", Service: "s", Name: "outoforder"}
+	now := time.Now().UTC()
+	exp := now.Add(30 * time.Second)
+	setWithExpiry(t, c, id, "value", &exp)
+	me := c.maps[c.shardOf(&id)][id.Compose()]
+	// A write with an earlier generation arriving late at the scheduler
+	c.scheduleExpiry(&id, now.Add(10*time.Second), me.Generation-1)
+	c.cancelExpiry(&id, me.Generation-1)
+	c.removeExpired(now.Add(20 * time.Second))
+	if _, found := getValue(c, id); !found {
+		t.Fatalf("stale expiry removed the current value")
+	}
+	c.removeExpired(now.Add(40 * time.Second))
+	if _, found := getValue(c, id); found {
+		t.Fatalf("item was not removed at its expiry")
+	}
+}
+
+// Test that an expired entry whose value has been replaced in the meantime is
+// ignored, even if it is still in the heap
+func TestExpiryStale(t *testing.T) {
+	c := newCache()
+	id := item.ID{Owner: "o", Service: "s", Name: "stale"}
+	now := time.Now().UTC()
+	exp := now.Add(10 * time.Second)
+	setWithExpiry(t, c, id, "first", &exp)
+	gen := c.maps[c.shardOf(&id)][id.Compose()].Generation
+	setWithExpiry(t, c, id, "second", nil)
+	// Put back the expiry of the first value, as if its removal had raced
+	// with the second write
+	c.scheduleExpiry(&id, exp, gen)
+	c.removeExpired(now.Add(20 * time.Second))
+	value, found := getValue(c, id)
+	if !found || value != "second" {
+		t.Fatalf("expiry of a cleared value removed the new value")
+	}
+}
+
+// Test that subscribers are sent an EXPIRED event only when the current value
+// expires, and that they stay subscribed after it
+func TestExpiryNotifiesSubscribers(t *testing.T) {
+	c := newCache()
+	id := item.ID{Owner: "o", Service: "s", Name: "notify"}
+	sub := c.Subscribe(id)
+	now := time.Now().UTC()
+	exp := now.Add(10 * time.Second)
+	setWithExpiry(t, c, id, "first", &exp)
+	setWithExpiry(t, c, id, "second", nil)
+	c.removeExpired(now.Add(20 * time.Second))
+	setWithExpiry(t, c, id, "third", &exp)
+	c.removeExpired(now.Add(20 * time.Second))
+	if _, found := getValue(c, id); found {
+		t.Fatalf("item was not removed at its expiry")
+	}
+	setWithExpiry(t, c, id, "fourth", nil)
+	expected := []Event{EventUpdated, EventUpdated, EventUpdated, EventExpired, EventUpdated}
+	for i, ev := range expected {
+		select {
+		case got := <-sub.ch:
+			if got.Event != ev {
+				t.Fatalf("subscriber event %d is %v, expected %v", i, got.Event, ev)
+			}
+		default:
+			t.Fatalf("subscriber missed event %d (%v)", i, ev)
+		}
+	}
+	if len(sub.ch) != 0 {
+		t.Fatalf("subscriber received unexpected events")
+	}
+}
+
+// The sorted linked list that was used before the expiry heap, kept here as a
+// baseline for the benchmarks below
+type listEntry struct {
+	next   *listEntry
+	Expiry time.Time
+}
+
+func listInsert(list **listEntry, exp time.Time) {
+	var prevl *listEntry
+	l := *list
+	for l != nil && exp.After(l.Expiry) {
+		prevl = l
+		l = l.next
+	}
+	newEntry := &listEntry{Expiry: exp, next: l}
+	if prevl == nil {
+		*list = newEntry
+	} else {
+		prevl.next = newEntry
+	}
+}
+
+var benchSizes = []int{1000, 10000, 100000}
+
+// Measure inserting one more expiring item when size items are already waiting
+func BenchmarkScheduleExpiry(b *testing.B) {
+	for _, size := range benchSizes {
+		b.Run(fmt.Sprintf("items=%d", size), func(b *testing.B) {
+			c := newCache()
+			now := time.Now()
+			r := rand.New(rand.NewSource(1))
+			for i := 0; i < size; i++ {
+				id := item.ID{Owner: "o", Service: "s", Name: fmt.Sprintf("pre%d", i)}
+				c.scheduleExpiry(&id, now.Add(time.Duration(r.Int63n(int64(time.Hour)))), uint64(i))
+			}
+			ids := make([]item.ID, b.N)
+			for i := range ids {
+				ids[i] = item.ID{Owner: "o", Service: "s", Name: fmt.Sprintf("new%d", i)}
+			}
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				c.scheduleExpiry(&ids[i], now.Add(time.Duration(r.Int63n(int64(time.Hour)))), uint64(size+i))
+			}
+		})
+	}
+}
+
+// Same as BenchmarkScheduleExpiry, with the sorted linked list
+func BenchmarkSortedListInsert(b *testing.B) {
+	for _, size := range benchSizes {
+		b.Run(fmt.Sprintf("items=%d", size), func(b *testing.B) {
+			now := time.Now()
+			r := rand.New(rand.NewSource(1))
+			// Build the initial list directly in sorted order, inserting one by
+			// one would take quadratic time
+			pre := make([]time.Time, size)
+			for i := range pre {
+				pre[i] = now.Add(time.Duration(r.Int63n(int64(time.Hour))))
+			}
+			sort.Slice(pre, func(i, j int) bool { return pre[i].Before(pre[j]) })
+			var list *listEntry
+			for i := size - 1; i >= 0; i-- {
+				list = &listEntry{Expiry: pre[i], next: list}
+			}
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				listInsert(&list, now.Add(time.Duration(r.Int63n(int64(time.Hour)))))
+			}
+		})
+	}
+}
+
+// Measure moving the expiry of an already scheduled item, as happens when a key
+// with an expiry is overwritten
+func BenchmarkRescheduleExpiry(b *testing.B) {
+	for _, size := range benchSizes {
+		b.Run(fmt.Sprintf("items=%d", size), func(b *testing.B) {
+			c := newCache()
+			now := time.Now()
+			r := rand.New(rand.NewSource(1))
+			ids := make([]item.ID, size)
+			for i := range ids {
+				ids[i] = item.ID{Owner: "o", Service: "s", Name: fmt.Sprintf("pre%d", i)}
+				c.scheduleExpiry(&ids[i], now.Add(time.Duration(r.Int63n(int64(time.Hour)))), uint64(size+i))
+			}
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				c.scheduleExpiry(&ids[i%size], now.Add(time.Duration(r.Int63n(int64(time.Hour)))), uint64(size+i))
+			}
+		})
+	}
+}
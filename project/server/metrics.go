@@ -0,0 +1,132 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"path"
+	"strconv"
+	"time"
+
+	"github.com/kamenlilovgocourse/gocourse/project/metrics"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Metrics updated by the RPC handlers. Those that describe the state of the
+// cache are collected from Cache.Stats when the metrics are written
+type serverMetrics struct {
+	gets     *metrics.CounterVec
+	hits     *metrics.CounterVec
+	misses   *metrics.CounterVec
+	sets     *metrics.CounterVec
+	requests *metrics.CounterVec
+	latency  *metrics.HistogramVec
+}
+
+// Metrics that describe the state of the cache, all collected from one call of
+// Cache.Stats when the metrics are written. Indexed by the constants below
+var statsMetrics = []metrics.Desc{
+	{Name: "cache_shard_items", Help: "Items in the cache, by shard.", Type: "gauge", Labels: []string{"shard"}},
+	{Name: "cache_shard_memory_bytes", Help: "Estimated memory used by the items in the cache, by shard.", Type: "gauge", Labels: []string{"shard"}},
+	{Name: "cache_expired_total", Help: "Items removed because they expired.", Type: "counter"},
+	{Name: "cache_evicted_total", Help: "Items evicted to keep the cache within its limits.", Type: "counter"},
+	{Name: "cache_subscriptions", Help: "Active item and pattern subscriptions.", Type: "gauge"},
+	{Name: "cache_subscriber_lag_max", Help: "The most updates waiting to be sent to any one subscriber.", Type: "gauge"},
+	{Name: "cache_subscriber_dropped_updates_total", Help: "Updates skipped for subscribers that did not keep up.", Type: "counter"},
+	{Name: "cache_subscriber_disconnects_total", Help: "Subscriptions ended because the subscriber did not keep up.", Type: "counter"},
+	{Name: "cache_expiry_queue_length", Help: "Items waiting to expire.", Type: "gauge"},
+}
+
+const (
+	metricShardItems = iota
+	metricShardMemory
+	metricExpired
+	metricEvicted
+	metricSubscriptions
+	metricSubscriberLag
+	metricDroppedUpdates
+	metricSlowDisconnects
+	metricExpiryQueue
+)
+
+// RegisterMetrics adds the metrics of the server and of its cache to reg, and
+// starts counting calls. Must be called before the server is used. The metrics
+// interceptors must be installed for the call counts and latencies to be kept
+func (s *CacheServer) RegisterMetrics(reg *metrics.Registry) {
+	s.metrics = &serverMetrics{
+		gets:     reg.NewCounterVec("cache_gets_total", "Items looked up, by RPC.", "rpc"),
+		hits:     reg.NewCounterVec("cache_hits_total", "Items looked up that were found, by RPC.", "rpc"),
+		misses:   reg.NewCounterVec("cache_misses_total", "Items looked up that were not found, by RPC.", "rpc"),
+		sets:     reg.NewCounterVec("cache_sets_total", "Items written, by RPC.", "rpc"),
+		requests: reg.NewCounterVec("cache_rpc_requests_total", "Completed RPCs, by method and status code.", "method", "code"),
+		latency:  reg.NewHistogramVec("cache_rpc_duration_seconds", "Time taken by unary RPCs, by method.", metrics.DefaultBuckets, "method"),
+	}
+	c := s.cache
+	reg.NewCollector(statsMetrics, func(emit func(int, float64, ...string)) {
+		st := c.Stats()
+		for i, sh := range st.Shards {
+			emit(metricShardItems, float64(sh.Items), strconv.Itoa(i))
+			emit(metricShardMemory, float64(sh.Memory), strconv.Itoa(i))
+		}
+		emit(metricExpired, float64(st.Expirations))
+		emit(metricEvicted, float64(st.Evictions))
+		emit(metricSubscriptions, float64(st.Subscriptions))
+		emit(metricSubscriberLag, float64(st.SubscriberLag))
+		emit(metricDroppedUpdates, float64(st.DroppedUpdates))
+		emit(metricSlowDisconnects, float64(st.SlowDisconnects))
+		emit(metricExpiryQueue, float64(st.PendingExpiries))
+	})
+}
+
+// Count a lookup of an item by an RPC as a hit or a miss, depending on the
+// error it returned, which may be an engine or a status error. Lookups that
+// failed for other reasons are not counted
+func (s *CacheServer) countGet(rpc string, err error) {
+	if s.metrics == nil {
+		return
+	}
+	switch {
+	case err == nil:
+		s.metrics.hits.With(rpc).Inc()
+	case errors.Is(err, ErrNotFound) || status.Code(err) == codes.NotFound:
+		s.metrics.misses.With(rpc).Inc()
+	default:
+		return
+	}
+	s.metrics.gets.With(rpc).Inc()
+}
+
+// Count a write of an item by an RPC
+func (s *CacheServer) countSet(rpc string) {
+	if s.metrics == nil {
+		return
+	}
+	s.metrics.sets.With(rpc).Inc()
+}
+
+// UnaryMetricsInterceptor counts unary calls by method and status code, and
+// records how long they take. It should come before the auth interceptor, so
+// that rejected calls are counted too
+func (s *CacheServer) UnaryMetricsInterceptor(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	if s.metrics == nil {
+		return handler(ctx, req)
+	}
+	start := time.Now()
+	resp, err := handler(ctx, req)
+	method := path.Base(info.FullMethod)
+	s.metrics.latency.With(method).Observe(time.Since(start).Seconds())
+	s.metrics.requests.With(method, status.Code(err).String()).Inc()
+	return resp, err
+}
+
+// StreamMetricsInterceptor counts streaming calls by method and status code when
+// they end. Their duration is not recorded, as subscriptions last as long as the
+// client wants them to
+func (s *CacheServer) StreamMetricsInterceptor(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	err := handler(srv, ss)
+	if s.metrics != nil {
+		s.metrics.requests.With(path.Base(info.FullMethod), status.Code(err).String()).Inc()
+	}
+	return err
+}
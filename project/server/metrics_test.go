@@ -0,0 +1,39 @@
+package server
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/kamenlilovgocourse/gocourse/project/cachegrpc"
+	"github.com/kamenlilovgocourse/gocourse/project/item"
+	"github.com/kamenlilovgocourse/gocourse/project/metrics"
+)
+
+// Test that lookups are counted as hits and misses, and that the size of the
+// cache is reported for the shard holding the item
+func TestMetrics(t *testing.T) {
+	s := NewServer(newCache())
+	reg := metrics.NewRegistry()
+	s.RegisterMetrics(reg)
+	ctx := context.Background()
+	s.SetItem(ctx, &cachegrpc.SetItemParams{Owner: "o", Service: "s", Name: "n", Value: "v"})
+	s.GetItem(ctx, &cachegrpc.GetItemParams{Owner: "o", Service: "s", Name: "n"})
+	s.GetItem(ctx, &cachegrpc.GetItemParams{Owner: "o", Service: "s", Name: "missing"})
+
+	var out strings.Builder
+	reg.WriteTo(&out)
+	id := item.ID{Owner: "o", Service: "s", Name: "n"}
+	for _, line := range []string{
+		`cache_gets_total{rpc="GetItem"} 2`,
+		`cache_hits_total{rpc="GetItem"} 1`,
+		`cache_misses_total{rpc="GetItem"} 1`,
+		`cache_sets_total{rpc="SetItem"} 1`,
+		`cache_shard_items{shard="` + strconv.Itoa(s.cache.shardOf(&id)) + `"} 1`,
+	} {
+		if !strings.Contains(out.String(), line+"\n") {
+			t.Fatalf("metrics do not contain %s:\n%s", line, out.String())
+		}
+	}
+}
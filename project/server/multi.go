@@ -0,0 +1,139 @@
+package server
+
+import (
+	"context"
+
+	"github.com/kamenlilovgocourse/gocourse/project/cachegrpc"
+	"github.com/kamenlilovgocourse/gocourse/project/item"
+	"google.golang.org/grpc/status"
+)
+
+// Group the indices of a batch of item IDs by the shard they live in, so that
+// each shard is locked only once per batch. Within a shard the indices keep
+// the order of the batch
+func (c *Cache) groupByShard(ids []item.ID) map[int][]int {
+	shards := make(map[int][]int)
+	for i := range ids {
+		hash := c.shardOf(&ids[i])
+		shards[hash] = append(shards[hash], i)
+	}
+	return shards
+}
+
+// GetMulti retrieves a batch of items, locking each shard only once. The results
+// and errors hold one entry per ID, in the same order
+func (c *Cache) GetMulti(ids []item.ID) ([]Item, []error) {
+	items := make([]Item, len(ids))
+	errs := make([]error, len(ids))
+	for hash, indices := range c.groupByShard(ids) {
+		c.mapsLock[hash].Lock()
+		for _, i := range indices {
+			if errs[i] = validateID(&ids[i]); errs[i] != nil {
+				continue
+			}
+			e, ok := c.maps[hash][ids[i].Compose()]
+			if ok && e.Present {
+				touchEntry(&e)
+				c.maps[hash][ids[i].Compose()] = e
+			}
+			items[i], errs[i] = itemOf(&ids[i], &e, ok)
+		}
+		c.mapsLock[hash].Unlock()
+	}
+	return items, errs
+}
+
+// SetMulti does a batch of sets, locking each shard only once. Each set is
+// handled as by Set, including its condition; a failed set does not prevent the
+// others. The results and errors hold one entry per request, in the same order
+func (c *Cache) SetMulti(reqs []SetRequest) ([]Item, []error) {
+	ids := make([]item.ID, len(reqs))
+	items := make([]Item, len(reqs))
+	errs := make([]error, len(reqs))
+	for i := range reqs {
+		ids[i] = reqs[i].ID
+		errs[i] = c.validateSet(&ids[i], reqs[i].Value, &reqs[i].SetOptions)
+	}
+	newMe := make([]mapEntry, len(reqs))
+	prevMe := make([]mapEntry, len(reqs))
+	for hash, indices := range c.groupByShard(ids) {
+		c.mapsLock[hash].Lock()
+		for _, i := range indices {
+			if errs[i] != nil {
+				continue
+			}
+			newMe[i], prevMe[i], errs[i] = c.setLocked(hash, &ids[i], reqs[i].Value, &reqs[i].SetOptions)
+		}
+		c.mapsLock[hash].Unlock()
+	}
+	for i := range ids {
+		if errs[i] == nil {
+			c.afterSet(&ids[i], &newMe[i], &prevMe[i])
+			items[i] = newMe[i].item()
+		}
+	}
+	return items, errs
+}
+
+// MultiGet retrieves the values of a batch of cache items in one call. The result
+// holds one entry per requested item, in the same order, with either the value or
+// the reason why it could not be retrieved
+func (s *CacheServer) MultiGet(ctx context.Context, p *cachegrpc.MultiGetParams) (*cachegrpc.MultiGetResult, error) {
+	ret := &cachegrpc.MultiGetResult{Items: make([]*cachegrpc.MultiGetItem, len(p.Items))}
+	// Only look up the items the caller may read, remembering where they go
+	var ids []item.ID
+	var pos []int
+	for i, ip := range p.Items {
+		id := item.ID{Owner: ip.Owner, Service: ip.Service, Name: ip.Name}
+		if err := s.checkAccess(ctx, &id, false); err != nil {
+			ret.Items[i] = &cachegrpc.MultiGetItem{Error: status.Convert(err).Message(), Code: int32(status.Code(err))}
+			continue
+		}
+		ids = append(ids, id)
+		pos = append(pos, i)
+	}
+	items, errs := s.cache.GetMulti(ids)
+	for j, i := range pos {
+		s.countGet("MultiGet", errs[j])
+		if err := toStatus(errs[j]); err != nil {
+			ret.Items[i] = &cachegrpc.MultiGetItem{Error: status.Convert(err).Message(), Code: int32(status.Code(err))}
+		} else {
+			ret.Items[i] = &cachegrpc.MultiGetItem{Result: getItemResult(&items[j])}
+		}
+	}
+	return ret, nil
+}
+
+// MultiSet sets a batch of cache items in one call. Each item is handled as by
+// SetItem, including its condition; a failed item does not prevent the others
+// from being set. The result holds one entry per item, in the same order
+func (s *CacheServer) MultiSet(ctx context.Context, p *cachegrpc.MultiSetParams) (*cachegrpc.MultiSetResult, error) {
+	ret := &cachegrpc.MultiSetResult{Items: make([]*cachegrpc.MultiSetItem, len(p.Items))}
+	// Only set the items the caller may write, remembering where they go
+	var reqs []SetRequest
+	var pos []int
+	for i, ip := range p.Items {
+		id := item.ID{Owner: ip.Owner, Service: ip.Service, Name: ip.Name}
+		err := s.checkAccess(ctx, &id, true)
+		var opts SetOptions
+		if err == nil {
+			opts, err = setOptions(&id, ip)
+		}
+		if err = toStatus(err); err != nil {
+			ret.Items[i] = &cachegrpc.MultiSetItem{Error: status.Convert(err).Message(), Code: int32(status.Code(err))}
+			continue
+		}
+		reqs = append(reqs, SetRequest{ID: id, Value: setValue(ip), SetOptions: opts})
+		pos = append(pos, i)
+	}
+	items, errs := s.cache.SetMulti(reqs)
+	for j, i := range pos {
+		if err := toStatus(errs[j]); err != nil {
+			ret.Items[i] = &cachegrpc.MultiSetItem{Error: status.Convert(err).Message(), Code: int32(status.Code(err))}
+		} else {
+			ret.Items[i] = &cachegrpc.MultiSetItem{Result: &cachegrpc.SetItemResult{Version: items[j].Version}}
+			s.countSet("MultiSet")
+		}
+	}
+	return ret, nil
+}
@@ -0,0 +1,105 @@
+package server
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/kamenlilovgocourse/gocourse/project/cachegrpc"
+	"github.com/kamenlilovgocourse/gocourse/project/item"
+	"google.golang.org/grpc/codes"
+)
+
+// Test that batches return their results in the order of the request, however
+// the items are spread over the shards, that a failed condition only fails its
+// own set, and that a repeated ID is handled once per occurrence, in order
+func TestGetSetMulti(t *testing.T) {
+	c := newCache()
+	var reqs []SetRequest
+	var ids []item.ID
+	for i := 0; i < 50; i++ {
+		id := item.ID{Owner: "o", Service: "s", Name: fmt.Sprintf("n%d", i)}
+		reqs = append(reqs, SetRequest{ID: id, Value: fmt.Sprintf("v%d", i)})
+		ids = append(ids, id)
+	}
+	_, errs := c.SetMulti(reqs)
+	for i, err := range errs {
+		if err != nil {
+			t.Fatalf("SetMulti() returned error %v for %s", err, ids[i].Compose())
+		}
+	}
+	items, errs := c.GetMulti(ids)
+	for i := range ids {
+		if errs[i] != nil || items[i].Value != fmt.Sprintf("v%d", i) {
+			t.Fatalf("GetMulti() returned %q, %v for %s, expected v%d", items[i].Value, errs[i], ids[i].Compose(), i)
+		}
+	}
+
+	n0 := item.ID{Owner: "o", Service: "s", Name: "n0"}
+	n1 := item.ID{Owner: "o", Service: "s", Name: "n1"}
+	items, errs = c.SetMulti([]SetRequest{
+		{ID: n0, Value: "a"},
+		{ID: n1, Value: "b", SetOptions: SetOptions{Condition: IfValue, ExpectedValue: "wrong"}},
+		{ID: n0, Value: "c", SetOptions: SetOptions{Condition: IfValue, ExpectedValue: "a"}},
+	})
+	if errs[0] != nil || errs[2] != nil || !errors.Is(errs[1], ErrValueMismatch) {
+		t.Fatalf("SetMulti() with a failed condition in the middle returned errors %v", errs)
+	}
+	if items[2].Version <= items[0].Version {
+		t.Fatalf("second set of the same item got version %d, expected more than %d", items[2].Version, items[0].Version)
+	}
+	items, errs = c.GetMulti([]item.ID{n0, n1, n0})
+	if errs[0] != nil || errs[1] != nil || errs[2] != nil || items[0].Value != "c" || items[1].Value != "v1" || items[2].Value != "c" {
+		t.Fatalf("GetMulti() returned %v, %v, expected c, v1, c", items, errs)
+	}
+}
+
+// Test that MultiGet and MultiSet report the failure of every item with its
+// status code, and still handle the other items
+func TestMultiGetSetErrors(t *testing.T) {
+	s := NewServer(newCache())
+	s.EnableAuth([]byte("secret"))
+	alice := authContext(t, s, s.issueToken("alice"))
+	if _, err := s.SetItem(authContext(t, s, s.issueToken("bob")), &cachegrpc.SetItemParams{Owner: "bob", Service: "s", Name: "n", Value: "v"}); err != nil {
+		t.Fatalf("SetItem() returned error %v", err)
+	}
+
+	set, err := s.MultiSet(alice, &cachegrpc.MultiSetParams{Items: []*cachegrpc.SetItemParams{
+		{Owner: "alice", Service: "s", Name: "n", Value: "v"},
+		{Owner: "alice", Service: "s", Value: "v"},
+		{Owner: "bob", Service: "s", Name: "n", Value: "w"},
+		{Owner: "alice", Service: "s", Name: "n", Value: "w", Condition: cachegrpc.SetCondition_IF_ABSENT},
+		{Owner: "alice", Service: "s", Name: "m", Value: "v"},
+	}})
+	if err != nil {
+		t.Fatalf("MultiSet() returned error %v", err)
+	}
+	setCodes := []codes.Code{codes.OK, codes.InvalidArgument, codes.PermissionDenied, codes.FailedPrecondition, codes.OK}
+	for i, code := range setCodes {
+		res := set.Items[i]
+		if codes.Code(res.Code) != code || (code == codes.OK) != (res.Result != nil) || (code == codes.OK) != (res.Error == "") {
+			t.Fatalf("MultiSet() item %d returned %v, expected code %v", i, res, code)
+		}
+	}
+
+	get, err := s.MultiGet(alice, &cachegrpc.MultiGetParams{Items: []*cachegrpc.GetItemParams{
+		{Owner: "alice", Service: "s", Name: "m"},
+		{Owner: "alice", Service: "s", Name: "missing"},
+		{Owner: "bob", Service: "s", Name: "n"},
+		{Owner: "alice", Service: "s"},
+		{Owner: "alice", Service: "s", Name: "n"},
+	}})
+	if err != nil {
+		t.Fatalf("MultiGet() returned error %v", err)
+	}
+	getCodes := []codes.Code{codes.OK, codes.NotFound, codes.PermissionDenied, codes.InvalidArgument, codes.OK}
+	for i, code := range getCodes {
+		res := get.Items[i]
+		if codes.Code(res.Code) != code || (code == codes.OK) != (res.Result != nil) || (code == codes.OK) != (res.Error == "") {
+			t.Fatalf("MultiGet() item %d returned %v, expected code %v", i, res, code)
+		}
+	}
+	if get.Items[0].Result.Value != "v" || get.Items[4].Result.Value != "v" {
+		t.Fatalf("MultiGet() returned values %q and %q, expected v", get.Items[0].Result.Value, get.Items[4].Result.Value)
+	}
+}
@@ -0,0 +1,87 @@
+package server
+
+import (
+	"github.com/kamenlilovgocourse/gocourse/project/cachegrpc"
+	"github.com/kamenlilovgocourse/gocourse/project/item"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// SubscribePattern returns a subscription to every item matching a pattern,
+// whether the item exists or not. The owner, service and name of the pattern may
+// contain the wildcards * and ?, see item.ID.Matches. If filter is not nil, only
+// changes of the items for which it returns true are delivered
+func (c *Cache) SubscribePattern(pattern item.ID, filter func(*item.ID) bool) *Subscription {
+	sub := c.newSubscription(pattern, true, filter)
+	c.patternSubsLock.Lock()
+	defer c.patternSubsLock.Unlock()
+	c.patternSubs = append(c.patternSubs, sub)
+	return sub
+}
+
+func (c *Cache) removePatternSub(s *Subscription) {
+	c.patternSubsLock.Lock()
+	defer c.patternSubsLock.Unlock()
+	for i := range c.patternSubs {
+		if c.patternSubs[i] == s {
+			c.patternSubs = append(c.patternSubs[:i], c.patternSubs[i+1:]...)
+			return
+		}
+	}
+}
+
+// Send an update to every pattern subscription that matches the item. Called
+// by publish with the shard lock of the item held, so that pattern subscribers
+// receive the changes of an item in the order they were made
+func (c *Cache) notifyPatternSubs(u Update) {
+	var matching []*Subscription
+	c.patternSubsLock.Lock()
+	for _, sub := range c.patternSubs {
+		if u.ID.Matches(&sub.id) && (sub.filter == nil || sub.filter(&u.ID)) {
+			matching = append(matching, sub)
+		}
+	}
+	c.patternSubsLock.Unlock()
+	for _, sub := range matching {
+		sub.deliver(u)
+	}
+}
+
+// Convert an update to the event sent to pattern subscribers and watchers,
+// which carries the ID of the item
+func patternEvent(u *Update) *cachegrpc.PatternEvent {
+	ev := &cachegrpc.PatternEvent{Owner: u.ID.Owner, Service: u.ID.Service, Name: u.ID.Name,
+		Version: u.Item.Version, Event: cachegrpc.ItemEvent(u.Event), ContentType: u.Item.ContentType, Flags: u.Item.Flags, Sequence: u.Seq}
+	ev.Value, ev.Data = splitValue(u.Item.Value)
+	if u.Item.Expiry != nil {
+		ev.Expiry = timestamppb.New(*u.Item.Expiry)
+	}
+	return ev
+}
+
+// Service the SubscribePattern API call. Works like SubscribeItem, but the owner,
+// service and name in the parameters may contain wildcards, and every event carries
+// the ID of the item that changed. Items the caller may not read are skipped
+func (s *CacheServer) SubscribePattern(p *cachegrpc.GetItemParams, stream cachegrpc.CacheServer_SubscribePatternServer) error {
+	caller := callerOf(stream.Context())
+	sub := s.cache.SubscribePattern(item.ID{Owner: p.Owner, Service: p.Service, Name: p.Name}, func(id *item.ID) bool {
+		return s.mayAccess(caller, id, false)
+	})
+	defer sub.Close()
+	for {
+		select {
+		case u := <-sub.Updates():
+			if err := stream.Send(patternEvent(&u)); err != nil {
+				return err
+			}
+		case <-sub.Overflowed():
+			return errSlowSubscriber(sub)
+		case <-stream.Context().Done():
+			return status.FromContextError(stream.Context().Err()).Err()
+		case <-s.stop:
+			return errShuttingDown
+		case <-s.cache.Done():
+			return errShuttingDown
+		}
+	}
+}
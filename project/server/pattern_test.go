@@ -0,0 +1,84 @@
+package server
+
+import (
+	"fmt"
+	"runtime"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/kamenlilovgocourse/gocourse/project/item"
+)
+
+// Test that pattern subscribers receive concurrent changes of an item in the
+// order the cache made them, and so end up with its final value
+func TestPatternSubscriberOrder(t *testing.T) {
+	c := newCache()
+	c.SetSubscriberQueue(10000, CoalesceSlowSubscribers)
+	id := item.ID{Owner: "o", Service: "s", Name: "n"}
+	// A filter that yields widens the window for notifications to overtake
+	// each other
+	sub := c.SubscribePattern(item.ID{Owner: "o", Service: "s", Name: "*"}, func(*item.ID) bool {
+		runtime.Gosched()
+		return true
+	})
+	defer sub.Close()
+
+	var wg sync.WaitGroup
+	for w := 0; w < 8; w++ {
+		wg.Add(1)
+		go func(w int) {
+			defer wg.Done()
+			for i := 0; i < 200; i++ {
+				c.Set(id, fmt.Sprintf("%d-%d", w, i), SetOptions{})
+			}
+		}(w)
+	}
+	wg.Wait()
+
+	var last Update
+	for sub.Lag() > 0 {
+		u := <-sub.Updates()
+		if u.Seq <= last.Seq {
+			t.Fatalf("pattern subscriber received sequence %d after %d", u.Seq, last.Seq)
+		}
+		last = u
+	}
+	if it, _ := c.Get(id); last.Item.Value != it.Value {
+		t.Fatalf("pattern subscriber ended with value %q, the item holds %q", last.Item.Value, it.Value)
+	}
+}
+
+// Test that pattern subscribers are told of every set, delete and expiry of
+// the items matching the pattern, and of nothing else
+func TestPatternSubscriberEvents(t *testing.T) {
+	c := newCache()
+	sub := c.SubscribePattern(item.ID{Owner: "o", Service: "s*", Name: "n?"}, nil)
+	defer sub.Close()
+	soon := time.Now().Add(time.Minute)
+
+	c.Set(item.ID{Owner: "o", Service: "s", Name: "n10"}, "v", SetOptions{})
+	c.Set(item.ID{Owner: "o", Service: "t", Name: "n1"}, "v", SetOptions{})
+	c.Set(item.ID{Owner: "p", Service: "s", Name: "n1"}, "v", SetOptions{})
+	c.Set(item.ID{Owner: "o", Service: "s", Name: "n1"}, "1", SetOptions{})
+	c.Set(item.ID{Owner: "o", Service: "svc", Name: "n2"}, "2", SetOptions{Expiry: &soon})
+	c.Delete(item.ID{Owner: "o", Service: "s", Name: "n1"})
+	c.removeExpired(soon.Add(time.Second))
+
+	want := []Update{
+		{ID: item.ID{Owner: "o", Service: "s", Name: "n1"}, Event: EventUpdated, Item: Item{Value: "1"}},
+		{ID: item.ID{Owner: "o", Service: "svc", Name: "n2"}, Event: EventUpdated, Item: Item{Value: "2"}},
+		{ID: item.ID{Owner: "o", Service: "s", Name: "n1"}, Event: EventDeleted},
+		{ID: item.ID{Owner: "o", Service: "svc", Name: "n2"}, Event: EventExpired},
+	}
+	for _, w := range want {
+		u := <-sub.Updates()
+		if u.ID != w.ID || u.Event != w.Event || u.Item.Value != w.Item.Value {
+			t.Fatalf("pattern subscriber received %v of %s with value %q, expected %v of %s with value %q",
+				u.Event, u.ID.Compose(), u.Item.Value, w.Event, w.ID.Compose(), w.Item.Value)
+		}
+	}
+	if n := sub.Lag(); n != 0 {
+		t.Fatalf("pattern subscriber has %d more updates, of items not matching the pattern", n)
+	}
+}
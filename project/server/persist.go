@@ -0,0 +1,328 @@
+package server
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"time"
+	"unicode/utf8"
+
+	"github.com/kamenlilovgocourse/gocourse/project/item"
+)
+
+const (
+	logFileName      = "cache.log"
+	oldLogFileName   = "cache.log.old"
+	snapshotFileName = "snapshot.json"
+)
+
+// A single record in the append-only log or in the snapshot file. Records
+// always carry the complete state of an entry, so replaying them more than
+// once has the same effect as replaying them once
+type logRecord struct {
+	Op      string `json:"op"`
+	Owner   string `json:"owner"`
+	Service string `json:"service"`
+	Name    string `json:"name"`
+	Value   string `json:"value,omitempty"`
+	// Values that are not valid UTF-8 are kept here instead, as JSON strings
+	// cannot hold them
+	Data        []byte     `json:"data,omitempty"`
+	ContentType string     `json:"content_type,omitempty"`
+	Flags       uint32     `json:"flags,omitempty"`
+	Expiry      *time.Time `json:"expiry,omitempty"`
+	Version     uint64     `json:"version,omitempty"`
+}
+
+// Return the record that restores an entry
+func setRecord(id *item.ID, me *mapEntry) *logRecord {
+	r := &logRecord{Op: opSet, Owner: id.Owner, Service: id.Service, Name: id.Name,
+		ContentType: me.ContentType, Flags: me.Flags, Expiry: me.Expiry, Version: me.Version}
+	if utf8.ValidString(me.Value) {
+		r.Value = me.Value
+	} else {
+		r.Data = []byte(me.Value)
+	}
+	return r
+}
+
+const (
+	opSet    = "set"
+	opDelete = "delete"
+	opExpire = "expire"
+	opEvict  = "evict"
+)
+
+// The persistence subsystem keeps every change to the cache in an append-only
+// log inside dataDir. Periodically the whole cache is written to a snapshot
+// file and the log is started over, so that the log does not grow without bound.
+// Changes are logged while the shard lock of the item is held, so the order of
+// records for an item in the log matches the order of the changes
+type persistence struct {
+	cache    *Cache
+	lock     sync.Mutex
+	dataDir  string
+	fsync    bool
+	logFile  *os.File
+	stop     chan struct{}
+	shutdown sync.WaitGroup
+}
+
+// EnablePersistence restores the cache contents from the snapshot and log files
+// in dataDir, skipping entries that have already expired, and then starts logging
+// every change to the cache. If fsync is set, every log record is synced to disk
+// before the call that caused it returns. A new snapshot is taken every
+// snapshotInterval, unless it is not positive, and a final one when the cache is
+// closed. Must be called before the cache is used
+func (c *Cache) EnablePersistence(dataDir string, fsync bool, snapshotInterval time.Duration) error {
+	if err := os.MkdirAll(dataDir, 0755); err != nil {
+		return err
+	}
+	state := make(map[string]logRecord)
+	if err := replayFile(filepath.Join(dataDir, snapshotFileName), state); err != nil {
+		return err
+	}
+	if err := replayFile(filepath.Join(dataDir, oldLogFileName), state); err != nil {
+		return err
+	}
+	if err := replayFile(filepath.Join(dataDir, logFileName), state); err != nil {
+		return err
+	}
+	now := time.Now().UTC()
+	restored := 0
+	for _, r := range state {
+		if r.Expiry != nil && !r.Expiry.After(now) {
+			continue
+		}
+		as := item.ID{Owner: r.Owner, Service: r.Service, Name: r.Name}
+		hash := c.shardOf(&as)
+		me := mapEntry{Value: r.Value, ContentType: r.ContentType, Flags: r.Flags, Expiry: r.Expiry, Present: true, Version: r.Version}
+		if r.Data != nil {
+			me.Value = string(r.Data)
+		}
+		me.Generation = atomic.AddUint64(&c.nextGeneration, 1)
+		// Versions of later sets must be above every restored one
+		if r.Version > c.nextVersion {
+			c.nextVersion = r.Version
+		}
+		touchEntry(&me)
+		c.mapsLock[hash].Lock()
+		c.maps[hash][as.Compose()] = me
+		c.account(hash, as.Compose(), nil, &me)
+		c.mapsLock[hash].Unlock()
+		if r.Expiry != nil {
+			c.scheduleExpiry(&as, *r.Expiry, me.Generation)
+		}
+		restored++
+	}
+	log.Printf("Restored %d items from %s\n", restored, dataDir)
+
+	logFile, err := os.OpenFile(filepath.Join(dataDir, logFileName), os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return err
+	}
+	p := &persistence{cache: c, dataDir: dataDir, fsync: fsync, logFile: logFile, stop: make(chan struct{})}
+	// Compact whatever was replayed right away, so that the log starts empty
+	if err := p.snapshot(); err != nil {
+		return err
+	}
+	c.persist = p
+	if snapshotInterval > 0 {
+		p.shutdown.Add(1)
+		go p.snapshotRoutine(snapshotInterval)
+	}
+	return nil
+}
+
+// Take a final snapshot and close the log file. It is a no-op if persistence
+// was never enabled
+func (c *Cache) closePersistence() error {
+	p := c.persist
+	if p == nil {
+		return nil
+	}
+	close(p.stop)
+	p.shutdown.Wait()
+	err := p.snapshot()
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	if cerr := p.logFile.Close(); err == nil {
+		err = cerr
+	}
+	return err
+}
+
+// Read a file of JSON log records, one per line, and apply them to state.
+// A missing file is not an error. A damaged record, which can be left at the
+// end of the log if the server was killed mid-write, ends the replay
+func replayFile(name string, state map[string]logRecord) error {
+	f, err := os.Open(name)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	dec := json.NewDecoder(bufio.NewReader(f))
+	for {
+		var r logRecord
+		err := dec.Decode(&r)
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			log.Printf("Stopping replay of %s at damaged record: %v\n", name, err)
+			return nil
+		}
+		as := item.ID{Owner: r.Owner, Service: r.Service, Name: r.Name}
+		switch r.Op {
+		case opSet:
+			state[as.Compose()] = r
+		case opDelete, opExpire, opEvict:
+			delete(state, as.Compose())
+		}
+	}
+}
+
+// Append a record to the log. Errors are reported but do not fail the cache
+// operation, which has already taken effect in memory
+func (p *persistence) append(r *logRecord) {
+	data, err := json.Marshal(r)
+	if err != nil {
+		log.Printf("Failed to encode log record: %v\n", err)
+		return
+	}
+	data = append(data, '\n')
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	if _, err := p.logFile.Write(data); err != nil {
+		log.Printf("Failed to write log record: %v\n", err)
+		return
+	}
+	if p.fsync {
+		if err := p.logFile.Sync(); err != nil {
+			log.Printf("Failed to sync log: %v\n", err)
+		}
+	}
+}
+
+// Move the current log aside and start a new one. Must be called with the
+// persistence lock held
+func (p *persistence) rotateLog() error {
+	logName := filepath.Join(p.dataDir, logFileName)
+	oldName := filepath.Join(p.dataDir, oldLogFileName)
+	if _, err := os.Stat(oldName); err == nil {
+		// A previous snapshot failed after rotating. Keep appending to the current
+		// log: together with the old one it still holds every change made since
+		// the last good snapshot
+		return nil
+	}
+	if err := p.logFile.Close(); err != nil {
+		return err
+	}
+	if err := os.Rename(logName, oldName); err != nil {
+		return err
+	}
+	f, err := os.OpenFile(logName, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return err
+	}
+	p.logFile = f
+	return nil
+}
+
+// Write all present items to a new snapshot file and replace the previous
+// snapshot with it. The log is rotated before the items are collected, so every
+// change that the snapshot might miss is in the new log, and replaying the
+// snapshot followed by the new log restores the latest state. Once the snapshot
+// is in place, the rotated log is no longer needed
+func (p *persistence) snapshot() error {
+	p.lock.Lock()
+	err := p.rotateLog()
+	p.lock.Unlock()
+	if err != nil {
+		return err
+	}
+	tmpName := filepath.Join(p.dataDir, snapshotFileName+".tmp")
+	f, err := os.Create(tmpName)
+	if err != nil {
+		return err
+	}
+	w := bufio.NewWriter(f)
+	enc := json.NewEncoder(w)
+	c := p.cache
+	for i := range c.maps {
+		c.mapsLock[i].Lock()
+		for key, me := range c.maps[i] {
+			if !me.Present {
+				continue
+			}
+			as := item.ID{}
+			as.Parse(key)
+			if err = enc.Encode(setRecord(&as, &me)); err != nil {
+				break
+			}
+		}
+		c.mapsLock[i].Unlock()
+		if err != nil {
+			f.Close()
+			return err
+		}
+	}
+	if err := w.Flush(); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Sync(); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	if err := os.Rename(tmpName, filepath.Join(p.dataDir, snapshotFileName)); err != nil {
+		return err
+	}
+	return os.Remove(filepath.Join(p.dataDir, oldLogFileName))
+}
+
+func (p *persistence) snapshotRoutine(interval time.Duration) {
+	defer p.shutdown.Done()
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			if err := p.snapshot(); err != nil {
+				log.Printf("Failed to take snapshot: %v\n", err)
+			}
+		case <-p.stop:
+			return
+		}
+	}
+}
+
+// Record a change to an item in the log, if persistence is enabled. Must be
+// called with the shard lock of the item held
+func (c *Cache) logSet(id *item.ID, me *mapEntry) {
+	if c.persist == nil {
+		return
+	}
+	c.persist.append(setRecord(id, me))
+}
+
+// Record the removal of an item in the log, if persistence is enabled. Must be
+// called with the shard lock of the item held
+func (c *Cache) logRemove(op string, id *item.ID) {
+	if c.persist == nil {
+		return
+	}
+	c.persist.append(&logRecord{Op: op, Owner: id.Owner, Service: id.Service, Name: id.Name})
+}
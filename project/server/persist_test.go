@@ -0,0 +1,197 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/kamenlilovgocourse/gocourse/project/cachegrpc"
+	"github.com/kamenlilovgocourse/gocourse/project/item"
+)
+
+// Test that values which are not valid UTF-8 survive a restart unchanged,
+// together with their content type and flags, and are returned in data
+func TestPersistBinaryValue(t *testing.T) {
+	dir := t.TempDir()
+	id := item.ID{Owner: "o", Service: "s", Name: "blob"}
+	blob := []byte{0xff, 0x00, 0xfe, 'x'}
+
+	c := New()
+	if err := c.EnablePersistence(dir, false, time.Hour); err != nil {
+		t.Fatalf("EnablePersistence() returned error %v", err)
+	}
+	s := NewServer(c)
+	_, err := s.SetItem(context.Background(), &cachegrpc.SetItemParams{Owner: id.Owner, Service: id.Service, Name: id.Name,
+		Data: blob, ContentType: "application/octet-stream", Flags: 7})
+	if err != nil {
+		t.Fatalf("SetItem() of binary data returned error %v", err)
+	}
+	if err := c.Close(); err != nil {
+		t.Fatalf("Close() returned error %v", err)
+	}
+
+	c = New()
+	defer c.Close()
+	if err := c.EnablePersistence(dir, false, time.Hour); err != nil {
+		t.Fatalf("EnablePersistence() after restart returned error %v", err)
+	}
+	res, err := NewServer(c).GetItem(context.Background(), &cachegrpc.GetItemParams{Owner: id.Owner, Service: id.Service, Name: id.Name})
+	if err != nil {
+		t.Fatalf("GetItem() after restart returned error %v", err)
+	}
+	if string(res.Data) != string(blob) || res.Value != "" {
+		t.Fatalf("GetItem() after restart returned value %q and data %v, expected data %v", res.Value, res.Data, blob)
+	}
+	if res.ContentType != "application/octet-stream" || res.Flags != 7 {
+		t.Fatalf("GetItem() after restart returned content type %q and flags %d", res.ContentType, res.Flags)
+	}
+}
+
+// Write log records to a file in dir, as the persistence does
+func writeRecords(t *testing.T, dir, name string, records ...logRecord) {
+	f, err := os.Create(filepath.Join(dir, name))
+	if err != nil {
+		t.Fatalf("creating %s: %v", name, err)
+	}
+	defer f.Close()
+	enc := json.NewEncoder(f)
+	for i := range records {
+		if err := enc.Encode(&records[i]); err != nil {
+			t.Fatalf("writing %s: %v", name, err)
+		}
+	}
+}
+
+// Start a cache restoring the contents of dir
+func restoreCache(t *testing.T, dir string) *Cache {
+	c := New()
+	t.Cleanup(func() { c.Close() })
+	if err := c.EnablePersistence(dir, false, time.Hour); err != nil {
+		t.Fatalf("EnablePersistence() returned error %v", err)
+	}
+	return c
+}
+
+// Copy the files of a data directory, so that they can be replayed while the
+// cache that wrote them still runs
+func copyDir(t *testing.T, dir string) string {
+	to := t.TempDir()
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatalf("reading %s: %v", dir, err)
+	}
+	for _, e := range entries {
+		data, err := os.ReadFile(filepath.Join(dir, e.Name()))
+		if err != nil {
+			t.Fatalf("reading %s: %v", e.Name(), err)
+		}
+		if err := os.WriteFile(filepath.Join(to, e.Name()), data, 0644); err != nil {
+			t.Fatalf("writing %s: %v", e.Name(), err)
+		}
+	}
+	return to
+}
+
+// Test that changes are restored from the log alone when the cache was not
+// closed, so no final snapshot was taken
+func TestPersistReplayLog(t *testing.T) {
+	dir := t.TempDir()
+	kept := item.ID{Owner: "o", Service: "s", Name: "kept"}
+	deleted := item.ID{Owner: "o", Service: "s", Name: "deleted"}
+	c := restoreCache(t, dir)
+	c.Set(kept, "1", SetOptions{})
+	last, _ := c.Set(kept, "2", SetOptions{})
+	c.Set(deleted, "v", SetOptions{})
+	if err := c.Delete(deleted); err != nil {
+		t.Fatalf("Delete() returned error %v", err)
+	}
+	version := last.Version
+
+	// Simulate a crash by starting over from the files as they are now
+	c = restoreCache(t, copyDir(t, dir))
+	if it, err := c.Get(kept); err != nil || it.Value != "2" || it.Version != version {
+		t.Fatalf("Get() after replay returned %v, %v, expected value 2 with version %d", it, err, version)
+	}
+	if _, err := c.Get(deleted); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("Get() of a deleted item after replay returned %v, expected ErrNotFound", err)
+	}
+	// Restored versions are not given out again
+	if it, _ := c.Set(deleted, "v", SetOptions{}); it.Version <= version {
+		t.Fatalf("Set() after replay returned version %d, expected more than %d", it.Version, version)
+	}
+}
+
+// Test that with a snapshot interval of zero no periodic snapshots are taken,
+// but closing the cache still writes a final one
+func TestPersistNoPeriodicSnapshot(t *testing.T) {
+	dir := t.TempDir()
+	id := item.ID{Owner: "o", Service: "s", Name: "n"}
+	c := New()
+	if err := c.EnablePersistence(dir, false, 0); err != nil {
+		t.Fatalf("EnablePersistence() returned error %v", err)
+	}
+	c.Set(id, "v", SetOptions{})
+	if err := c.Close(); err != nil {
+		t.Fatalf("Close() returned error %v", err)
+	}
+	// The final snapshot holds every change, so the log was started over
+	if fi, err := os.Stat(filepath.Join(dir, logFileName)); err != nil || fi.Size() != 0 {
+		t.Fatalf("log after Close() is %v, %v, expected an empty file", fi, err)
+	}
+	if it, err := restoreCache(t, dir).Get(id); err != nil || it.Value != "v" {
+		t.Fatalf("Get() after restart returned %v, %v, expected value v", it, err)
+	}
+}
+
+// Test that records of items that expired while the server was down are not
+// restored
+func TestPersistSkipExpired(t *testing.T) {
+	dir := t.TempDir()
+	past := time.Now().Add(-time.Minute).UTC()
+	future := time.Now().Add(time.Hour).UTC()
+	writeRecords(t, dir, logFileName,
+		logRecord{Op: opSet, Owner: "o", Service: "s", Name: "expired", Value: "v", Expiry: &past, Version: 1},
+		logRecord{Op: opSet, Owner: "o", Service: "s", Name: "live", Value: "v", Expiry: &future, Version: 2})
+
+	c := restoreCache(t, dir)
+	if _, err := c.Get(item.ID{Owner: "o", Service: "s", Name: "expired"}); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("Get() of an expired record returned %v, expected ErrNotFound", err)
+	}
+	if it, err := c.Get(item.ID{Owner: "o", Service: "s", Name: "live"}); err != nil || it.Expiry == nil || !it.Expiry.Equal(future) {
+		t.Fatalf("Get() of a record expiring later returned %v, %v", it, err)
+	}
+}
+
+// Test that a server which died after rotating the log, but before replacing
+// the snapshot, restores the changes of the rotated log too, in order
+func TestPersistRecoverRotation(t *testing.T) {
+	dir := t.TempDir()
+	writeRecords(t, dir, snapshotFileName,
+		logRecord{Op: opSet, Owner: "o", Service: "s", Name: "a", Value: "snapshot", Version: 1},
+		logRecord{Op: opSet, Owner: "o", Service: "s", Name: "b", Value: "snapshot", Version: 2})
+	writeRecords(t, dir, oldLogFileName,
+		logRecord{Op: opSet, Owner: "o", Service: "s", Name: "a", Value: "old log", Version: 3},
+		logRecord{Op: opDelete, Owner: "o", Service: "s", Name: "b"},
+		logRecord{Op: opSet, Owner: "o", Service: "s", Name: "c", Value: "old log", Version: 4})
+	writeRecords(t, dir, logFileName,
+		logRecord{Op: opSet, Owner: "o", Service: "s", Name: "c", Value: "log", Version: 5})
+
+	c := restoreCache(t, dir)
+	want := map[string]string{"a": "old log", "c": "log"}
+	for name, value := range want {
+		if it, err := c.Get(item.ID{Owner: "o", Service: "s", Name: name}); err != nil || it.Value != value {
+			t.Fatalf("Get() of %s returned %v, %v, expected %q", name, it, err, value)
+		}
+	}
+	if _, err := c.Get(item.ID{Owner: "o", Service: "s", Name: "b"}); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("Get() of an item deleted in the rotated log returned %v, expected ErrNotFound", err)
+	}
+	// The snapshot taken on startup holds everything, so the rotated log is gone
+	if _, err := os.Stat(filepath.Join(dir, oldLogFileName)); !errors.Is(err, os.ErrNotExist) {
+		t.Fatalf("%s still present after restoring: %v", oldLogFileName, err)
+	}
+}
@@ -2,154 +2,238 @@ package server
 
 import (
 	"context"
-	"errors"
 	"fmt"
+	"strconv"
 	"sync"
 	"sync/atomic"
 	"time"
+	"unicode/utf8"
 
 	"github.com/kamenlilovgocourse/gocourse/project/cachegrpc"
 	"github.com/kamenlilovgocourse/gocourse/project/item"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/durationpb"
 	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
-// A map entry consists of the value of the cache item, an optional expiry,
-// plus a (empty or nonempty) slice of subscriptions. If subscriptions are
-// present, every time the value is updated, the appropriate subscriber
-// listeners goroutines are notified via the channel so they can generate
-// a push notification to a connected client
-type mapEntry struct {
-	Value  string
-	Expiry *time.Time
-	Subs   []chan struct{}
-}
-
-var (
-	nextClientId   int64
-	mapsLock       [item.IDMapsCount]sync.Mutex
-	maps           [item.IDMapsCount]map[string]mapEntry
-	StopServerChan chan struct{}
-)
+// Returned to subscribers whose streams are ended by Shutdown, or because the
+// cache was closed
+var errShuttingDown = status.Error(codes.Unavailable, "server is shutting down")
 
-func init() {
-	for i := 0; i < item.IDMapsCount; i++ {
-		maps[i] = make(map[string]mapEntry)
+// Returned to subscribers whose streams are ended for not keeping up with the
+// changes, under DisconnectSlowSubscribers
+func errSlowSubscriber(sub *Subscription) error {
+	st := status.Newf(codes.ResourceExhausted, "subscriber fell more than %d updates behind", cap(sub.ch))
+	detail, err := st.WithDetails(&errdetails.ErrorInfo{
+		Reason:   "SLOW_SUBSCRIBER",
+		Domain:   "cacheserver",
+		Metadata: map[string]string{"queue_size": strconv.Itoa(cap(sub.ch))},
+	})
+	if err == nil {
+		st = detail
 	}
-	StopServerChan = make(chan struct{})
+	return st.Err()
 }
 
-// The gRPC CacheServer service with out own implementations
+// The gRPC CacheServer service with out own implementations. It serves a Cache,
+// and adds what only makes sense for remote clients: client IDs, authentication
+// and access grants
 type CacheServer struct {
 	cachegrpc.UnimplementedCacheServerServer
+	cache        *Cache
+	nextClientId int64
+	// Closed by Shutdown, ends the subscription streams
+	stop     chan struct{}
+	stopOnce sync.Once
+
+	// Secret used to sign client tokens; authentication is disabled while nil
+	authSecret []byte
+	// Set by RequireClientCerts
+	requireCerts bool
+	aclLock      sync.Mutex
+	// Grants by owner:service, then by grantee client ID
+	acls map[string]map[string]grant
+
+	// Set by RegisterMetrics; calls are not counted while nil
+	metrics *serverMetrics
 }
 
-func NewServer() *CacheServer {
-	s := &CacheServer{}
+// NewServer returns a gRPC service for the given cache
+func NewServer(cache *Cache) *CacheServer {
+	s := &CacheServer{cache: cache, stop: make(chan struct{}), acls: make(map[string]map[string]grant)}
 	return s
 }
 
+// Shutdown ends all SubscribeItem and SubscribePattern streams with an Unavailable
+// status, so that a graceful stop of the gRPC server does not wait for them. It may
+// be called more than once
+func (s *CacheServer) Shutdown() {
+	s.stopOnce.Do(func() {
+		close(s.stop)
+	})
+}
+
 // GetClientID uses a globally unique, incrementing int counter to provide unique IDs to cache
-// clients that are interested in having one
-func (s *CacheServer) GetClientID(context.Context, *cachegrpc.AssignClientID) (*cachegrpc.AssignedClientID, error) {
+// clients that are interested in having one. When authentication is enabled the ID is random
+// instead, and comes with the token the client must present in later calls. Clients with a
+// TLS certificate get the ID the certificate stands for
+func (s *CacheServer) GetClientID(ctx context.Context, p *cachegrpc.AssignClientID) (*cachegrpc.AssignedClientID, error) {
 	ret := &cachegrpc.AssignedClientID{}
-	ret.Id = fmt.Sprintf("%d", atomic.AddInt64(&nextClientId, 1))
+	if caller := callerOf(ctx); caller != "" {
+		ret.Id = caller
+		return ret, nil
+	}
+	if s.authSecret != nil {
+		ret.Id = randomClientID()
+		ret.Token = s.issueToken(ret.Id)
+		return ret, nil
+	}
+	ret.Id = fmt.Sprintf("%d", atomic.AddInt64(&s.nextClientId, 1))
 	return ret, nil
 }
 
+// Return the value of a SetItem call, which is sent in data if it is not UTF-8
+func setValue(p *cachegrpc.SetItemParams) string {
+	if len(p.Data) > 0 {
+		return string(p.Data)
+	}
+	return p.Value
+}
+
+// Return the expiry given in a call either as an absolute time or as a time to
+// live, which is counted from now, or nil if neither is given
+func resolveExpiry(id *item.ID, expiry *timestamppb.Timestamp, ttl *durationpb.Duration) (*time.Time, error) {
+	switch {
+	case expiry != nil && ttl != nil:
+		return nil, itemError(id, ErrExpiryAndTTL, "")
+	case expiry != nil:
+		exp := expiry.AsTime()
+		return &exp, nil
+	case ttl != nil:
+		if !ttl.IsValid() || ttl.AsDuration() <= 0 {
+			return nil, itemError(id, ErrInvalidTTL, ttl.AsDuration().String())
+		}
+		exp := time.Now().UTC().Add(ttl.AsDuration())
+		return &exp, nil
+	}
+	return nil, nil
+}
+
+// Convert the optional parts of a SetItem call to the options of Cache.Set
+func setOptions(id *item.ID, p *cachegrpc.SetItemParams) (SetOptions, error) {
+	opts := SetOptions{Condition: Condition(p.Condition), Version: p.Version, ExpectedValue: p.ExpectedValue,
+		ContentType: p.ContentType, Flags: p.Flags}
+	var err error
+	opts.Expiry, err = resolveExpiry(id, p.Expiry, p.Ttl)
+	return opts, err
+}
+
+// Split a value into the string or bytes field it is returned in. Protobuf
+// strings must be valid UTF-8, so other values can only go in the bytes field
+func splitValue(value string) (string, []byte) {
+	if utf8.ValidString(value) {
+		return value, nil
+	}
+	return "", []byte(value)
+}
+
+// Format an item as returned to the client
+func getItemResult(it *Item) *cachegrpc.GetItemResult {
+	res := &cachegrpc.GetItemResult{Version: it.Version, ContentType: it.ContentType, Flags: it.Flags}
+	res.Value, res.Data = splitValue(it.Value)
+	if it.Expiry != nil {
+		res.Expiry = timestamppb.New(*it.Expiry)
+	}
+	return res
+}
+
 // SetItem sets a cache item with a given ID, value and optional expiry on the server. If the
 // item value was already set, and any subscribers are attached to it, they are notified that the
-// value is updated so they can push a notification to a connected client
+// value is updated so they can push a notification to a connected client. Every successful set
+// gives the entry a new version; a condition can be supplied so the set only happens if the
+// item is absent, or its current version or value match the expected ones
 func (s *CacheServer) SetItem(ctx context.Context, p *cachegrpc.SetItemParams) (*cachegrpc.SetItemResult, error) {
 	as := item.ID{Owner: p.Owner, Service: p.Service, Name: p.Name}
-	hash := as.HashKey()
-	me := mapEntry{Value: p.Value}
-	me.Subs = make([]chan struct{}, 0)
-	if p.Expiry != nil {
-		exp := p.Expiry.AsTime()
-		me.Expiry = &exp
-	}
-	mapsLock[hash].Lock()
-	prevMe, found := maps[hash][as.Compose()]
-	if found {
-		me.Subs = prevMe.Subs
-	}
-	maps[hash][as.Compose()] = me
-	mapsLock[hash].Unlock()
-	if p.Expiry != nil {
-		insertInExpList(&as, p.Expiry.AsTime())
-	}
-	for _, notify := range me.Subs {
-		notify <- struct{}{}
-	}
-	ret := &cachegrpc.SetItemResult{}
-	return ret, nil
+	if err := s.checkAccess(ctx, &as, true); err != nil {
+		return nil, err
+	}
+	opts, err := setOptions(&as, p)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	it, err := s.cache.Set(as, setValue(p), opts)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	s.countSet("SetItem")
+	return &cachegrpc.SetItemResult{Version: it.Version}, nil
 }
 
 // Retrieve the value of a previously set cache item
 func (s *CacheServer) GetItem(ctx context.Context, p *cachegrpc.GetItemParams) (*cachegrpc.GetItemResult, error) {
 	as := item.ID{Owner: p.Owner, Service: p.Service, Name: p.Name}
-	hash := as.HashKey()
-	mapsLock[hash].Lock()
-	result, ok := maps[hash][as.Compose()]
-	mapsLock[hash].Unlock()
-	resultFmt := cachegrpc.GetItemResult{}
-	if !ok {
-		return &resultFmt, errors.New("Item " + as.Compose() + " not found")
-	}
-	resultFmt.Value = result.Value
-	if result.Expiry != nil {
-		resultFmt.Expiry = timestamppb.New(*result.Expiry)
-	}
-	return &resultFmt, nil
+	if err := s.checkAccess(ctx, &as, false); err != nil {
+		return nil, err
+	}
+	it, err := s.cache.Get(as)
+	s.countGet("GetItem", err)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return getItemResult(&it), nil
 }
 
-// Helper routine: given a chan struct{}, and a slice of chan structs, locate
-// the entry and remove it from the slice, returning the new slice
-func remove(slice []chan struct{}, s chan struct{}) []chan struct{} {
-	for i := 0; i < len(slice); i++ {
-		if slice[i] == s {
-			return append(slice[:i], slice[i+1:]...)
-		}
+// DeleteItem removes a previously set cache item, together with any pending expiry for it.
+// Subscribers attached to the item are notified with a DELETED event; their subscriptions
+// are kept, so that a later SetItem of the same ID still reaches them
+func (s *CacheServer) DeleteItem(ctx context.Context, p *cachegrpc.GetItemParams) (*cachegrpc.DeleteItemResult, error) {
+	as := item.ID{Owner: p.Owner, Service: p.Service, Name: p.Name}
+	if err := s.checkAccess(ctx, &as, true); err != nil {
+		return nil, err
 	}
-	return nil
+	if err := s.cache.Delete(as); err != nil {
+		return nil, toStatus(err)
+	}
+	return &cachegrpc.DeleteItemResult{}, nil
 }
 
 // Service the SubscribeItem API call. This will typically be invoked by a client from a dedicated
 // goroutine that will expect the server to occasionally send it notifications that the item with
-// the specified ID has been updated, and this routine will send the updated value
-func (s *CacheServer) SubscribeItem(p *cachegrpc.GetItemParams, stream cachegrpc.CacheServer_SubscribeItemServer) error {
+// the specified ID has been updated, and this routine will send the updated value. The
+// subscription is removed as soon as the client cancels the call or goes away. The client may
+// ask for the current value first, or for the changes it missed since an earlier subscription
+func (s *CacheServer) SubscribeItem(p *cachegrpc.SubscribeParams, stream cachegrpc.CacheServer_SubscribeItemServer) error {
 	as := item.ID{Owner: p.Owner, Service: p.Service, Name: p.Name}
-	hash := as.HashKey()
-	var thisChan = make(chan struct{})
-	mapsLock[hash].Lock()
-	e, found := maps[hash][as.Compose()]
-	if !found {
-		e = mapEntry{Value: "", Expiry: nil}
-	}
-	e.Subs = append(maps[hash][as.Compose()].Subs, thisChan)
-	maps[hash][as.Compose()] = e
-	mapsLock[hash].Unlock()
+	if err := validateID(&as); err != nil {
+		return toStatus(err)
+	}
+	if err := s.checkAccess(stream.Context(), &as, false); err != nil {
+		return err
+	}
+	sub, err := s.cache.SubscribeWith(as, SubscribeOptions{Initial: p.Initial, Resume: p.Resume, After: p.FromSequence})
+	if err != nil {
+		return toStatus(err)
+	}
+	defer sub.Close()
 	for {
 		select {
-		case <-thisChan:
-			// Go on
-		case <-StopServerChan:
-			return nil
-		}
-		mapsLock[hash].Lock()
-		e := maps[hash][as.Compose()]
-		item := cachegrpc.GetItemResult{Value: e.Value}
-		if e.Expiry != nil {
-			item.Expiry = timestamppb.New(*e.Expiry)
-		}
-		mapsLock[hash].Unlock()
-		err := stream.Send(&item)
-		if err != nil {
-			mapsLock[hash].Lock()
-			e := maps[hash][as.Compose()]
-			e.Subs = remove(e.Subs, thisChan)
-			mapsLock[hash].Unlock()
-			return err
+		case u := <-sub.Updates():
+			res := getItemResult(&u.Item)
+			res.Event = cachegrpc.ItemEvent(u.Event)
+			res.Sequence = u.Seq
+			if err := stream.Send(res); err != nil {
+				return err
+			}
+		case <-sub.Overflowed():
+			return errSlowSubscriber(sub)
+		case <-stream.Context().Done():
+			return status.FromContextError(stream.Context().Err()).Err()
+		case <-s.stop:
+			return errShuttingDown
+		case <-s.cache.Done():
+			return errShuttingDown
 		}
 	}
 }
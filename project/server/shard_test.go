@@ -0,0 +1,68 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/kamenlilovgocourse/gocourse/project/cachegrpc"
+)
+
+// Test that items set by parallel clients are spread evenly over the shards,
+// whatever their number, so that no shard lock is much busier than the others
+func TestShardDistribution(t *testing.T) {
+	const perShard = 200
+	for _, shards := range []int{16, 128, 1000} {
+		c := newShardedCache(shards)
+		s := NewServer(c)
+		var wg sync.WaitGroup
+		for w := 0; w < 8; w++ {
+			wg.Add(1)
+			go func(w int) {
+				defer wg.Done()
+				for i := w; i < shards*perShard; i += 8 {
+					p := &cachegrpc.SetItemParams{Owner: "1", Service: "session", Name: fmt.Sprintf("user%d", i), Value: "v"}
+					if _, err := s.SetItem(context.Background(), p); err != nil {
+						t.Errorf("SetItem() returned error %v", err)
+						return
+					}
+				}
+			}(w)
+		}
+		wg.Wait()
+		st := c.Stats()
+		if len(st.Shards) != shards {
+			t.Fatalf("Stats() returned %d shards, expected %d", len(st.Shards), shards)
+		}
+		for i, shard := range st.Shards {
+			if shard.Items < perShard*70/100 || shard.Items > perShard*130/100 {
+				t.Fatalf("shard %d of %d holds %d items, expected about %d", i, shards, shard.Items, perShard)
+			}
+		}
+	}
+}
+
+// Measure SetItem and GetItem called from parallel goroutines on different
+// items, with more or fewer shards to spread the lock contention over
+func BenchmarkParallelSetGet(b *testing.B) {
+	for _, shards := range []int{1, 16, 128, 1024} {
+		b.Run(fmt.Sprintf("shards=%d", shards), func(b *testing.B) {
+			s := NewServer(newShardedCache(shards))
+			var worker int64
+			b.RunParallel(func(pb *testing.PB) {
+				w := atomic.AddInt64(&worker, 1)
+				ctx := context.Background()
+				set := &cachegrpc.SetItemParams{Owner: fmt.Sprint(w), Service: "bench", Value: "v"}
+				get := &cachegrpc.GetItemParams{Owner: set.Owner, Service: "bench"}
+				for i := 0; pb.Next(); i++ {
+					set.Name = fmt.Sprintf("item%d", i%1000)
+					s.SetItem(ctx, set)
+					get.Name = set.Name
+					s.GetItem(ctx, get)
+				}
+			})
+		})
+	}
+}
@@ -0,0 +1,83 @@
+package server
+
+import (
+	"sort"
+	"sync/atomic"
+
+	"github.com/kamenlilovgocourse/gocourse/project/item"
+)
+
+// Stats is a snapshot of the size and activity of a cache
+type Stats struct {
+	Items int64
+	// Estimated memory used by the items, in bytes
+	Memory          int64
+	Evictions       uint64
+	Expirations     uint64
+	PendingExpiries int
+	Subscriptions   int64
+	// The most updates waiting for any one subscriber, the updates dropped
+	// for subscribers that did not keep up, and the subscriptions ended for it
+	SubscriberLag   int
+	DroppedUpdates  uint64
+	SlowDisconnects uint64
+	// Items and memory of every shard, indexed by shard
+	Shards []ShardStats
+	// Every open subscription, ordered by the item or pattern subscribed to
+	Subscribers []SubscriberStats
+}
+
+// ShardStats holds the size of one shard of a cache
+type ShardStats struct {
+	Items  int64
+	Memory int64
+}
+
+// SubscriberStats holds the updates waiting for, and dropped from, one
+// subscription
+type SubscriberStats struct {
+	// The item, or the pattern of items, subscribed to
+	ID      item.ID
+	Pattern bool
+	Lag     int
+	Dropped uint64
+}
+
+// Stats returns the current size and activity counters of the cache. The
+// counters are read one at a time while the cache keeps changing, so they need
+// not be exactly consistent with each other
+func (c *Cache) Stats() Stats {
+	st := Stats{
+		Items:           atomic.LoadInt64(&c.itemCount),
+		Memory:          atomic.LoadInt64(&c.memoryUsed),
+		Evictions:       atomic.LoadUint64(&c.evictions),
+		Expirations:     atomic.LoadUint64(&c.expirations),
+		Subscriptions:   atomic.LoadInt64(&c.subscriptions),
+		DroppedUpdates:  atomic.LoadUint64(&c.droppedUpdates),
+		SlowDisconnects: atomic.LoadUint64(&c.slowDisconnects),
+		Shards:          make([]ShardStats, len(c.maps)),
+	}
+	for i := range st.Shards {
+		st.Shards[i] = ShardStats{Items: atomic.LoadInt64(&c.shardItems[i]), Memory: atomic.LoadInt64(&c.shardMemory[i])}
+	}
+	c.subsLock.Lock()
+	for sub := range c.subs {
+		lag := sub.Lag()
+		if lag > st.SubscriberLag {
+			st.SubscriberLag = lag
+		}
+		st.Subscribers = append(st.Subscribers, SubscriberStats{ID: sub.id, Pattern: sub.pattern, Lag: lag, Dropped: sub.Dropped()})
+	}
+	c.subsLock.Unlock()
+	sort.Slice(st.Subscribers, func(i, j int) bool {
+		a, b := &st.Subscribers[i], &st.Subscribers[j]
+		if a.ID != b.ID {
+			return a.ID.Compose() < b.ID.Compose()
+		}
+		return !a.Pattern && b.Pattern
+	})
+	c.expLock.Lock()
+	st.PendingExpiries = len(c.expQueue)
+	c.expLock.Unlock()
+	return st
+}
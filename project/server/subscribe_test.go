@@ -0,0 +1,177 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/kamenlilovgocourse/gocourse/project/cachegrpc"
+	"github.com/kamenlilovgocourse/gocourse/project/item"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Test that writers do not wait for a subscriber that does not read, and that
+// the subscriber skips ahead to the latest values once it does
+func TestSlowSubscriberCoalesce(t *testing.T) {
+	c := newCache()
+	c.SetSubscriberQueue(4, CoalesceSlowSubscribers)
+	id := item.ID{Owner: "o", Service: "s", Name: "n"}
+	sub := c.Subscribe(id)
+	defer sub.Close()
+	for i := 0; i < 10; i++ {
+		c.Set(id, fmt.Sprint(i), SetOptions{})
+	}
+	if sub.Lag() != 4 || sub.Dropped() != 6 {
+		t.Fatalf("subscriber has lag %d and dropped %d updates, expected 4 and 6", sub.Lag(), sub.Dropped())
+	}
+	if st := c.Stats(); st.SubscriberLag != 4 || st.DroppedUpdates != 6 {
+		t.Fatalf("Stats() returned lag %d and %d dropped updates, expected 4 and 6", st.SubscriberLag, st.DroppedUpdates)
+	}
+	for i := 6; i < 10; i++ {
+		if u := <-sub.Updates(); u.Item.Value != fmt.Sprint(i) {
+			t.Fatalf("subscriber received value %q, expected %d", u.Item.Value, i)
+		}
+	}
+}
+
+// Test that a subscriber that falls too far behind is dropped under
+// DisconnectSlowSubscribers, without holding up the writers
+func TestSlowSubscriberDisconnect(t *testing.T) {
+	c := newCache()
+	c.SetSubscriberQueue(2, DisconnectSlowSubscribers)
+	id := item.ID{Owner: "o", Service: "s", Name: "n"}
+	sub := c.Subscribe(id)
+	defer sub.Close()
+	c.Set(id, "1", SetOptions{})
+	c.Set(id, "2", SetOptions{})
+	select {
+	case <-sub.Overflowed():
+		t.Fatalf("subscription overflowed with a full, but not overflowing, queue")
+	default:
+	}
+	c.Set(id, "3", SetOptions{})
+	c.Set(id, "4", SetOptions{})
+	select {
+	case <-sub.Overflowed():
+	default:
+		t.Fatalf("subscription did not overflow")
+	}
+	if st := c.Stats(); st.SlowDisconnects != 1 {
+		t.Fatalf("Stats() returned %d slow disconnects, expected 1", st.SlowDisconnects)
+	}
+}
+
+// Server streams that only have a context, and discard what is sent on them
+type fakeStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (f *fakeStream) Context() context.Context { return f.ctx }
+
+type itemStream struct{ *fakeStream }
+
+func (s itemStream) Send(*cachegrpc.GetItemResult) error { return nil }
+
+type patternStream struct{ *fakeStream }
+
+func (s patternStream) Send(*cachegrpc.PatternEvent) error { return nil }
+
+// Test that subscription streams end, and their subscriptions are removed, as
+// soon as the client cancels the call, even if the item never changes
+func TestSubscriptionCancel(t *testing.T) {
+	s := NewServer(newCache())
+	p := &cachegrpc.GetItemParams{Owner: "o", Service: "s", Name: "n"}
+	for _, subscribe := range []func(*fakeStream) error{
+		func(f *fakeStream) error {
+			return s.SubscribeItem(&cachegrpc.SubscribeParams{Owner: p.Owner, Service: p.Service, Name: p.Name}, itemStream{f})
+		},
+		func(f *fakeStream) error { return s.SubscribePattern(p, patternStream{f}) },
+	} {
+		ctx, cancel := context.WithCancel(context.Background())
+		ended := make(chan error)
+		go func() { ended <- subscribe(&fakeStream{ctx: ctx}) }()
+		for s.cache.Stats().Subscriptions != 1 {
+			time.Sleep(time.Millisecond)
+		}
+		cancel()
+		select {
+		case err := <-ended:
+			if status.Code(err) != codes.Canceled {
+				t.Fatalf("cancelled subscription returned %v, expected Canceled", err)
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatalf("subscription did not end when its call was cancelled")
+		}
+		if n := s.cache.Stats().Subscriptions; n != 0 {
+			t.Fatalf("%d subscriptions left after the call was cancelled", n)
+		}
+	}
+}
+
+// Test that a subscription asking for the initial value receives it first,
+// numbered before the changes that follow it
+func TestSubscribeInitial(t *testing.T) {
+	c := newCache()
+	id := item.ID{Owner: "o", Service: "s", Name: "n"}
+	sub, err := c.SubscribeWith(id, SubscribeOptions{Initial: true})
+	if err != nil {
+		t.Fatalf("SubscribeWith() returned error %v", err)
+	}
+	defer sub.Close()
+	absent := <-sub.Updates()
+	if absent.Event != EventAbsent {
+		t.Fatalf("subscriber to a missing item received event %v first, expected EventAbsent", absent.Event)
+	}
+	c.Set(id, "v", SetOptions{})
+	if u := <-sub.Updates(); u.Event != EventUpdated || u.Seq <= absent.Seq {
+		t.Fatalf("subscriber received event %v with sequence %d after %d", u.Event, u.Seq, absent.Seq)
+	}
+
+	sub2, _ := c.SubscribeWith(id, SubscribeOptions{Initial: true})
+	defer sub2.Close()
+	if u := <-sub2.Updates(); u.Event != EventCurrent || u.Item.Value != "v" {
+		t.Fatalf("subscriber received %v first, expected the current value", u)
+	}
+}
+
+// Test that a subscription can be resumed without missing the changes made
+// while it was closed, as long as they are still kept
+func TestSubscribeResume(t *testing.T) {
+	c := newCache()
+	c.SetSubscriberHistory(3)
+	id := item.ID{Owner: "o", Service: "s", Name: "n"}
+	sub := c.Subscribe(id)
+	c.Set(id, "1", SetOptions{})
+	last := <-sub.Updates()
+	sub.Close()
+
+	c.Set(id, "2", SetOptions{})
+	c.Set(id, "3", SetOptions{})
+	sub, err := c.SubscribeWith(id, SubscribeOptions{Resume: true, After: last.Seq})
+	if err != nil {
+		t.Fatalf("SubscribeWith() resuming returned error %v", err)
+	}
+	c.Delete(id)
+	for _, want := range []Update{{Event: EventUpdated, Item: Item{Value: "2"}}, {Event: EventUpdated, Item: Item{Value: "3"}}, {Event: EventDeleted}} {
+		u := <-sub.Updates()
+		if u.Event != want.Event || u.Item.Value != want.Item.Value || u.Seq <= last.Seq {
+			t.Fatalf("resumed subscriber received %v with sequence %d after %d, expected %v", u.Event, u.Seq, last.Seq, want)
+		}
+		last = u
+	}
+	sub.Close()
+
+	c.Set(id, "4", SetOptions{})
+	_, err = c.SubscribeWith(id, SubscribeOptions{Resume: true, After: last.Seq - 3})
+	if !errors.Is(err, ErrHistoryGone) || status.Code(toStatus(err)) != codes.OutOfRange {
+		t.Fatalf("resuming after a change that is no longer kept returned %v, expected ErrHistoryGone", err)
+	}
+	if _, err := c.SubscribeWith(item.ID{Owner: "o", Service: "s", Name: "new"}, SubscribeOptions{Resume: true}); !errors.Is(err, ErrHistoryGone) {
+		t.Fatalf("resuming a subscription to an unknown item returned %v, expected ErrHistoryGone", err)
+	}
+}
@@ -0,0 +1,129 @@
+package server
+
+import (
+	"context"
+	"sync/atomic"
+	"time"
+
+	"github.com/kamenlilovgocourse/gocourse/project/cachegrpc"
+	"github.com/kamenlilovgocourse/gocourse/project/item"
+	"google.golang.org/protobuf/types/known/durationpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// Touch changes the expiry of an item without changing its value or version,
+// and returns the item. A nil expiry makes the item stay until it is deleted.
+// Touching counts as an access for the eviction policies. Subscribers are not
+// notified, as the value does not change
+func (c *Cache) Touch(id item.ID, expiry *time.Time) (Item, error) {
+	if err := c.validateSet(&id, "", &SetOptions{Expiry: expiry}); err != nil {
+		return Item{}, err
+	}
+	hash := c.shardOf(&id)
+	c.mapsLock[hash].Lock()
+	me, ok := c.maps[hash][id.Compose()]
+	if !ok || !me.Present {
+		c.mapsLock[hash].Unlock()
+		return Item{}, itemError(&id, ErrNotFound, "")
+	}
+	prevExpiry := me.Expiry
+	me.Expiry = expiry
+	// A new generation, so that the pending expiry of the previous one does
+	// not remove the item
+	me.Generation = atomic.AddUint64(&c.nextGeneration, 1)
+	touchEntry(&me)
+	c.maps[hash][id.Compose()] = me
+	c.logSet(&id, &me)
+	c.mapsLock[hash].Unlock()
+	if expiry != nil {
+		c.scheduleExpiry(&id, *expiry, me.Generation)
+	} else if prevExpiry != nil {
+		c.cancelExpiry(&id, me.Generation)
+	}
+	return me.item(), nil
+}
+
+// TTL returns how long the item with the given ID has left to live, and false
+// if it does not expire. Unlike Get, it does not count as an access
+func (c *Cache) TTL(id item.ID) (time.Duration, bool, error) {
+	if err := validateID(&id); err != nil {
+		return 0, false, err
+	}
+	hash := c.shardOf(&id)
+	c.mapsLock[hash].Lock()
+	me, ok := c.maps[hash][id.Compose()]
+	c.mapsLock[hash].Unlock()
+	if !ok || !me.Present {
+		return 0, false, itemError(&id, ErrNotFound, "")
+	}
+	if me.Expiry == nil {
+		return 0, false, nil
+	}
+	// An item that is due may not have been removed yet
+	ttl := time.Until(*me.Expiry)
+	if ttl < 0 {
+		ttl = 0
+	}
+	return ttl, true, nil
+}
+
+// Touch changes the expiry of a cache item to an absolute time, or to a time to
+// live counted from when the server receives the call. Without either, the item
+// stops expiring
+func (s *CacheServer) Touch(ctx context.Context, p *cachegrpc.TouchParams) (*cachegrpc.TouchResult, error) {
+	it, err := s.touch(ctx, p, false)
+	if err != nil {
+		return nil, err
+	}
+	res := &cachegrpc.TouchResult{Version: it.Version}
+	if it.Expiry != nil {
+		res.Expiry = timestamppb.New(*it.Expiry)
+	}
+	return res, nil
+}
+
+// GetAndTouch retrieves a cache item and changes its expiry as Touch does, so
+// that items which are still in use keep living, such as sessions. Unlike
+// Touch, it needs an expiry or a time to live, so that a get can not make an
+// item stop expiring by mistake
+func (s *CacheServer) GetAndTouch(ctx context.Context, p *cachegrpc.TouchParams) (*cachegrpc.GetItemResult, error) {
+	it, err := s.touch(ctx, p, true)
+	s.countGet("GetAndTouch", err)
+	if err != nil {
+		return nil, err
+	}
+	return getItemResult(&it), nil
+}
+
+func (s *CacheServer) touch(ctx context.Context, p *cachegrpc.TouchParams, needExpiry bool) (Item, error) {
+	as := item.ID{Owner: p.Owner, Service: p.Service, Name: p.Name}
+	if err := s.checkAccess(ctx, &as, true); err != nil {
+		return Item{}, err
+	}
+	if needExpiry && p.Expiry == nil && p.Ttl == nil {
+		return Item{}, toStatus(itemError(&as, ErrNoExpiry, ""))
+	}
+	expiry, err := resolveExpiry(&as, p.Expiry, p.Ttl)
+	if err != nil {
+		return Item{}, toStatus(err)
+	}
+	it, err := s.cache.Touch(as, expiry)
+	return it, toStatus(err)
+}
+
+// TTL returns how long a cache item has left to live
+func (s *CacheServer) TTL(ctx context.Context, p *cachegrpc.GetItemParams) (*cachegrpc.TTLResult, error) {
+	as := item.ID{Owner: p.Owner, Service: p.Service, Name: p.Name}
+	if err := s.checkAccess(ctx, &as, false); err != nil {
+		return nil, err
+	}
+	ttl, expires, err := s.cache.TTL(as)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	res := &cachegrpc.TTLResult{HasExpiry: expires}
+	if expires {
+		res.Ttl = durationpb.New(ttl)
+	}
+	return res, nil
+}
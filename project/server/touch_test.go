@@ -0,0 +1,65 @@
+package server
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/kamenlilovgocourse/gocourse/project/cachegrpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+// Test that a TTL is counted by the server, that Touch moves or removes the
+// expiry of an item without changing its value or version, and that
+// GetAndTouch only moves it
+func TestTouch(t *testing.T) {
+	c := newCache()
+	s := NewServer(c)
+	ctx := context.Background()
+	get := &cachegrpc.GetItemParams{Owner: "o", Service: "s", Name: "n"}
+	touch := &cachegrpc.TouchParams{Owner: "o", Service: "s", Name: "n"}
+
+	_, err := s.SetItem(ctx, &cachegrpc.SetItemParams{Owner: "o", Service: "s", Name: "n", Value: "v", Ttl: durationpb.New(time.Minute)})
+	if err != nil {
+		t.Fatalf("SetItem() with a ttl returned error %v", err)
+	}
+	ttl, err := s.TTL(ctx, get)
+	if err != nil || !ttl.HasExpiry || ttl.Ttl.AsDuration() > time.Minute || ttl.Ttl.AsDuration() < 59*time.Second {
+		t.Fatalf("TTL() after SetItem() with a ttl of 1m returned %v, %v", ttl, err)
+	}
+
+	touch.Ttl = durationpb.New(time.Hour)
+	res, err := s.GetAndTouch(ctx, touch)
+	if err != nil || res.Value != "v" || res.Version != 1 {
+		t.Fatalf("GetAndTouch() returned %v, %v, expected the unchanged value and version", res, err)
+	}
+	// The expiry scheduled by SetItem no longer applies
+	c.removeExpired(time.Now().Add(2 * time.Minute))
+	if _, err := s.GetItem(ctx, get); err != nil {
+		t.Fatalf("touched item expired at its previous expiry: %v", err)
+	}
+
+	touch.Ttl = nil
+	if _, err := s.GetAndTouch(ctx, touch); status.Code(err) != codes.InvalidArgument {
+		t.Fatalf("GetAndTouch() without expiry returned %v, expected InvalidArgument", err)
+	}
+	if ttl, err := s.TTL(ctx, get); err != nil || !ttl.HasExpiry {
+		t.Fatalf("TTL() after a failed GetAndTouch() returned %v, %v, expected the expiry to be kept", ttl, err)
+	}
+	if _, err := s.Touch(ctx, touch); err != nil {
+		t.Fatalf("Touch() without expiry returned error %v", err)
+	}
+	if ttl, err := s.TTL(ctx, get); err != nil || ttl.HasExpiry {
+		t.Fatalf("TTL() after Touch() without expiry returned %v, %v, expected no expiry", ttl, err)
+	}
+	if len(c.expQueue) != 0 {
+		t.Fatalf("Touch() without expiry left %d pending expiries", len(c.expQueue))
+	}
+
+	touch.Ttl = durationpb.New(-time.Second)
+	if _, err := s.Touch(ctx, touch); status.Code(err) != codes.InvalidArgument {
+		t.Fatalf("Touch() with a negative ttl returned %v, expected InvalidArgument", err)
+	}
+}
@@ -0,0 +1,202 @@
+package server
+
+import (
+	"context"
+	"io"
+	"sync"
+
+	"github.com/kamenlilovgocourse/gocourse/project/cachegrpc"
+	"github.com/kamenlilovgocourse/gocourse/project/item"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Events waiting to be sent on a Watch stream. When it is full, the watches
+// stop taking updates from their subscriptions, so that a client that does not
+// keep up is handled by the slow subscriber policy of the cache
+const watchQueueSize = 64
+
+// The watches of one Watch stream, by the watch ID the client chose
+type watchStream struct {
+	server *CacheServer
+	ctx    context.Context
+	caller string
+	out    chan *cachegrpc.WatchEvent
+
+	lock    sync.Mutex
+	watches map[uint64]*watch
+}
+
+// A watch of a Watch stream. Its ID stays in use after UNWATCH until its end
+// has been reported, so that the events of a new watch with the same ID can not
+// come before that
+type watch struct {
+	sub     *Subscription
+	removed bool
+}
+
+// Service the Watch API call. Requests are read on a goroutine of their own,
+// every watch forwards the updates of its subscription on another, and this
+// goroutine sends the events of all of them, as only one may send at a time.
+// When the client closes its side of the stream, the existing watches keep
+// going until the call ends
+func (s *CacheServer) Watch(stream cachegrpc.CacheServer_WatchServer) error {
+	ctx, cancel := context.WithCancel(stream.Context())
+	defer cancel()
+	w := &watchStream{server: s, ctx: ctx, caller: callerOf(ctx), out: make(chan *cachegrpc.WatchEvent, watchQueueSize),
+		watches: make(map[uint64]*watch)}
+	defer w.closeAll()
+	recvErr := make(chan error, 1)
+	go func() {
+		recvErr <- w.receive(stream)
+	}()
+	for {
+		select {
+		case ev := <-w.out:
+			if err := stream.Send(ev); err != nil {
+				return err
+			}
+		case err := <-recvErr:
+			if err != io.EOF {
+				return err
+			}
+			recvErr = nil
+		case <-ctx.Done():
+			return status.FromContextError(ctx.Err()).Err()
+		case <-s.stop:
+			return errShuttingDown
+		case <-s.cache.Done():
+			return errShuttingDown
+		}
+	}
+}
+
+// Handle the requests of the client until its side of the stream ends
+func (w *watchStream) receive(stream cachegrpc.CacheServer_WatchServer) error {
+	for {
+		req, err := stream.Recv()
+		if err != nil {
+			return err
+		}
+		switch req.Action {
+		case cachegrpc.WatchAction_WATCH_ITEM, cachegrpc.WatchAction_WATCH_PATTERN:
+			w.add(req)
+		case cachegrpc.WatchAction_UNWATCH:
+			w.remove(req.WatchId)
+		default:
+			w.send(watchFailed(req.WatchId, status.Errorf(codes.InvalidArgument, "unknown action %d", req.Action), false))
+		}
+	}
+}
+
+// Queue an event to be sent, unless the call has ended
+func (w *watchStream) send(ev *cachegrpc.WatchEvent) {
+	select {
+	case w.out <- ev:
+	case <-w.ctx.Done():
+	}
+}
+
+// Return the event reporting that a watch failed with err, and whether the
+// watch ended with it
+func watchFailed(watchID uint64, err error, ended bool) *cachegrpc.WatchEvent {
+	st := status.Convert(err)
+	return &cachegrpc.WatchEvent{WatchId: watchID, Code: int32(st.Code()), Error: st.Message(), Ended: ended}
+}
+
+// Start a watch of an item or a pattern. Failures to subscribe are reported as
+// events of the watch, rather than ending the call
+func (w *watchStream) add(req *cachegrpc.WatchRequest) {
+	w.lock.Lock()
+	_, inUse := w.watches[req.WatchId]
+	w.lock.Unlock()
+	if inUse {
+		w.send(watchFailed(req.WatchId, status.Errorf(codes.AlreadyExists, "watch id %d is in use", req.WatchId), false))
+		return
+	}
+	s := w.server
+	id := item.ID{Owner: req.Owner, Service: req.Service, Name: req.Name}
+	var sub *Subscription
+	if req.Action == cachegrpc.WatchAction_WATCH_PATTERN {
+		sub = s.cache.SubscribePattern(id, func(id *item.ID) bool {
+			return s.mayAccess(w.caller, id, false)
+		})
+	} else {
+		err := toStatus(validateID(&id))
+		if err == nil {
+			err = s.checkAccess(w.ctx, &id, false)
+		}
+		if err == nil {
+			sub, err = s.cache.SubscribeWith(id, SubscribeOptions{Initial: req.Initial, Resume: req.Resume, After: req.FromSequence})
+			err = toStatus(err)
+		}
+		if err != nil {
+			w.send(watchFailed(req.WatchId, err, true))
+			return
+		}
+	}
+	wt := &watch{sub: sub}
+	w.lock.Lock()
+	w.watches[req.WatchId] = wt
+	w.lock.Unlock()
+	go w.forward(req.WatchId, wt)
+}
+
+// Stop a watch. Its forwarder reports the end, after any updates it already
+// took from the subscription, and only then frees the ID
+func (w *watchStream) remove(watchID uint64) {
+	w.lock.Lock()
+	wt := w.watches[watchID]
+	found := wt != nil && !wt.removed
+	if found {
+		wt.removed = true
+	}
+	w.lock.Unlock()
+	if !found {
+		w.send(watchFailed(watchID, status.Errorf(codes.NotFound, "no watch with id %d", watchID), true))
+		return
+	}
+	wt.sub.Close()
+}
+
+// Send the updates of a watch until it is removed, or dropped for not keeping
+// up, and free its ID once the end is queued
+func (w *watchStream) forward(watchID uint64, wt *watch) {
+	sub := wt.sub
+	for {
+		select {
+		case u := <-sub.Updates():
+			w.send(&cachegrpc.WatchEvent{WatchId: watchID, Change: patternEvent(&u)})
+		case <-sub.Overflowed():
+			sub.Close()
+			w.send(watchFailed(watchID, errSlowSubscriber(sub), true))
+			w.release(watchID, wt)
+			return
+		case <-sub.done:
+			w.send(&cachegrpc.WatchEvent{WatchId: watchID, Ended: true})
+			w.release(watchID, wt)
+			return
+		case <-w.ctx.Done():
+			return
+		}
+	}
+}
+
+// Free the ID of a watch that has ended, unless the call has ended too
+func (w *watchStream) release(watchID uint64, wt *watch) {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+	if w.watches[watchID] == wt {
+		delete(w.watches, watchID)
+	}
+}
+
+// End all watches when the call ends
+func (w *watchStream) closeAll() {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+	for watchID, wt := range w.watches {
+		wt.sub.Close()
+		delete(w.watches, watchID)
+	}
+}
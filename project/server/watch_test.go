@@ -0,0 +1,116 @@
+package server
+
+import (
+	"context"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/kamenlilovgocourse/gocourse/project/cachegrpc"
+	"google.golang.org/grpc/codes"
+)
+
+// A Watch stream fed from, and sending to, channels
+type fakeWatchStream struct {
+	*fakeStream
+	requests chan *cachegrpc.WatchRequest
+	events   chan *cachegrpc.WatchEvent
+}
+
+func (f *fakeWatchStream) Recv() (*cachegrpc.WatchRequest, error) {
+	req, ok := <-f.requests
+	if !ok {
+		return nil, io.EOF
+	}
+	return req, nil
+}
+
+func (f *fakeWatchStream) Send(ev *cachegrpc.WatchEvent) error {
+	f.events <- ev
+	return nil
+}
+
+// Test that item and pattern watches can be added and removed on one stream,
+// and that their events are tagged with their watch IDs
+func TestWatch(t *testing.T) {
+	s := NewServer(newCache())
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	stream := &fakeWatchStream{fakeStream: &fakeStream{ctx: ctx}, requests: make(chan *cachegrpc.WatchRequest), events: make(chan *cachegrpc.WatchEvent)}
+	go s.Watch(stream)
+	next := func() *cachegrpc.WatchEvent {
+		select {
+		case ev := <-stream.events:
+			return ev
+		case <-time.After(5 * time.Second):
+			t.Fatalf("no event received")
+			return nil
+		}
+	}
+
+	stream.requests <- &cachegrpc.WatchRequest{WatchId: 1, Action: cachegrpc.WatchAction_WATCH_ITEM, Owner: "o", Service: "s", Name: "a", Initial: true}
+	if ev := next(); ev.WatchId != 1 || ev.Change.GetEvent() != cachegrpc.ItemEvent_ABSENT {
+		t.Fatalf("item watch with initial received %v first, expected ABSENT", ev)
+	}
+	stream.requests <- &cachegrpc.WatchRequest{WatchId: 2, Action: cachegrpc.WatchAction_WATCH_PATTERN, Owner: "o", Service: "s", Name: "*"}
+	stream.requests <- &cachegrpc.WatchRequest{WatchId: 2, Action: cachegrpc.WatchAction_WATCH_PATTERN, Owner: "o", Service: "t", Name: "*"}
+	if ev := next(); ev.WatchId != 2 || codes.Code(ev.Code) != codes.AlreadyExists || ev.Ended {
+		t.Fatalf("adding a watch with an ID in use returned %v, expected AlreadyExists", ev)
+	}
+	for s.cache.Stats().Subscriptions != 2 {
+		time.Sleep(time.Millisecond)
+	}
+
+	s.SetItem(ctx, &cachegrpc.SetItemParams{Owner: "o", Service: "s", Name: "a", Value: "v"})
+	got := map[uint64]string{}
+	for i := 0; i < 2; i++ {
+		ev := next()
+		got[ev.WatchId] = ev.Change.GetValue()
+	}
+	if got[1] != "v" || got[2] != "v" {
+		t.Fatalf("watches received %v, expected the new value on both", got)
+	}
+
+	stream.requests <- &cachegrpc.WatchRequest{WatchId: 1, Action: cachegrpc.WatchAction_UNWATCH}
+	if ev := next(); ev.WatchId != 1 || !ev.Ended || ev.Code != 0 {
+		t.Fatalf("removing a watch returned %v, expected its end", ev)
+	}
+	// The ID of a removed watch is only free once its end has been reported, so
+	// the events of a new watch with the ID never come before that
+	rewatch := &cachegrpc.WatchRequest{WatchId: 2, Action: cachegrpc.WatchAction_WATCH_ITEM, Owner: "o", Service: "s", Name: "a", Initial: true}
+	for i := 0; i < 20; i++ {
+		stream.requests <- &cachegrpc.WatchRequest{WatchId: 2, Action: cachegrpc.WatchAction_UNWATCH}
+		stream.requests <- rewatch
+		ended := false
+		for {
+			ev := next()
+			if ev.WatchId != 2 {
+				t.Fatalf("re-adding watch 2 received %v", ev)
+			}
+			if ev.Ended {
+				if ended {
+					t.Fatalf("watch 2 ended twice")
+				}
+				ended = true
+				continue
+			}
+			if codes.Code(ev.Code) == codes.AlreadyExists {
+				stream.requests <- rewatch
+				continue
+			}
+			if !ended || ev.Change.GetValue() != "v" {
+				t.Fatalf("re-added watch received %v before the end of the old one", ev)
+			}
+			break
+		}
+	}
+	stream.requests <- &cachegrpc.WatchRequest{WatchId: 3, Action: cachegrpc.WatchAction_WATCH_ITEM, Owner: "o", Service: "s"}
+	if ev := next(); ev.WatchId != 3 || codes.Code(ev.Code) != codes.InvalidArgument || !ev.Ended {
+		t.Fatalf("watching an item without a name returned %v, expected InvalidArgument", ev)
+	}
+
+	cancel()
+	for s.cache.Stats().Subscriptions != 0 {
+		time.Sleep(time.Millisecond)
+	}
+}